@@ -0,0 +1,421 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Adjacency finds neighbourhoods whose boundaries share an edge. Boundaries
+// published by different forces rarely coincide exactly, so edges are shared
+// where they lie within Tolerance metres of each other.
+type Adjacency struct {
+	// Tolerance is the greatest distance in metres between two boundaries
+	// for them to be considered to touch.
+	Tolerance float64
+	// MinSharedLength is the length in metres of boundary two neighbourhoods
+	// must share to be adjacent, so that neighbourhoods whose boundaries
+	// only brush against each other are not adjacent.
+	MinSharedLength float64
+}
+
+// NewAdjacency returns an Adjacency with a tolerance of 20m and a minimum
+// shared length of 100m.
+func NewAdjacency() *Adjacency {
+	return &Adjacency{Tolerance: 20, MinSharedLength: 100}
+}
+
+// AdjacencyEdge records that two neighbourhoods share SharedLength metres of
+// boundary.
+type AdjacencyEdge struct {
+	A            NeighbourhoodRef `json:"a"`
+	B            NeighbourhoodRef `json:"b"`
+	SharedLength float64          `json:"shared_length"`
+}
+
+func (e AdjacencyEdge) String() string {
+	return Stringify(e)
+}
+
+// CrossForce reports whether the neighbourhoods belong to different forces.
+func (e AdjacencyEdge) CrossForce() bool {
+	return e.A.Force != e.B.Force
+}
+
+// NeighbourhoodHop is a neighbourhood and the number of borders crossed to
+// reach it.
+type NeighbourhoodHop struct {
+	NeighbourhoodRef
+	Hops int `json:"hops"`
+}
+
+// AdjacencyGraph is an undirected graph of neighbourhoods joined where they
+// share a boundary.
+type AdjacencyGraph struct {
+	nodes []NeighbourhoodRef
+	edges map[NeighbourhoodRef]map[NeighbourhoodRef]float64
+}
+
+// BoundariesFromCrawls returns the boundary of every neighbourhood in the
+// crawls whose boundary has at least three valid points.
+func BoundariesFromCrawls(crawls ...*ForceCrawl) map[NeighbourhoodRef][]Point {
+	boundaries := make(map[NeighbourhoodRef][]Point)
+	for _, crawl := range crawls {
+		for _, d := range crawl.Neighbourhoods {
+			var ring []Point
+			for _, l := range d.Boundary {
+				if p, err := l.Point(); err == nil {
+					ring = append(ring, p)
+				}
+			}
+			if len(ring) >= 3 {
+				boundaries[NeighbourhoodRef{Force: crawl.Force, Neighbourhood: d.Neighbourhood.ID}] = ring
+			}
+		}
+	}
+	return boundaries
+}
+
+// vertex is a point projected onto a plane in metres.
+type vertex struct{ x, y float64 }
+
+// boundary is a ring projected onto a plane with its bounding box.
+type boundary struct {
+	ring     []vertex
+	min, max vertex
+}
+
+func newBoundary(ring []Point, pl planar) boundary {
+	b := boundary{min: vertex{math.Inf(1), math.Inf(1)}, max: vertex{math.Inf(-1), math.Inf(-1)}}
+	for _, p := range ring {
+		x, y := pl.project(p)
+		b.ring = append(b.ring, vertex{x, y})
+		b.min = vertex{math.Min(b.min.x, x), math.Min(b.min.y, y)}
+		b.max = vertex{math.Max(b.max.x, x), math.Max(b.max.y, y)}
+	}
+	return b
+}
+
+// near reports whether the segment from p to q comes within d of the
+// bounding box of b.
+func (b boundary) near(p, q vertex, d float64) bool {
+	return math.Max(p.x, q.x) >= b.min.x-d && math.Min(p.x, q.x) <= b.max.x+d &&
+		math.Max(p.y, q.y) >= b.min.y-d && math.Min(p.y, q.y) <= b.max.y+d
+}
+
+// Graph builds the adjacency graph of the provided boundaries, which may
+// come from several forces. An error is returned if Tolerance or
+// MinSharedLength is negative.
+func (a *Adjacency) Graph(boundaries map[NeighbourhoodRef][]Point) (*AdjacencyGraph, error) {
+	if !(a.Tolerance >= 0) {
+		return nil, errors.New("adjacency tolerance must not be negative")
+	}
+	if !(a.MinSharedLength >= 0) {
+		return nil, errors.New("adjacency minimum shared length must not be negative")
+	}
+	g := &AdjacencyGraph{edges: make(map[NeighbourhoodRef]map[NeighbourhoodRef]float64)}
+	for ref := range boundaries {
+		g.nodes = append(g.nodes, ref)
+	}
+	sortRefs(g.nodes)
+
+	// one projection around the mean latitude is accurate to a few percent
+	// across Great Britain, which is ample for a tolerance.
+	var sum float64
+	var n int
+	for _, ring := range boundaries {
+		for _, p := range ring {
+			sum += p.Latitude
+			n++
+		}
+	}
+	pl := newPlanar(0)
+	if n > 0 {
+		pl = newPlanar(sum / float64(n))
+	}
+	projected := make([]boundary, len(g.nodes))
+	for i, ref := range g.nodes {
+		projected[i] = newBoundary(boundaries[ref], pl)
+	}
+
+	for i := range g.nodes {
+		for j := i + 1; j < len(g.nodes); j++ {
+			if !projected[i].near(projected[j].min, projected[j].max, a.Tolerance) {
+				continue
+			}
+			shared := math.Min(
+				sharedLength(projected[i], projected[j], a.Tolerance),
+				sharedLength(projected[j], projected[i], a.Tolerance),
+			)
+			if shared > 0 && shared >= a.MinSharedLength {
+				g.addEdge(g.nodes[i], g.nodes[j], shared)
+			}
+		}
+	}
+	return g, nil
+}
+
+// sharedLength returns the length of the boundary of a lying within
+// tolerance of, and running within 45 degrees of parallel to, the boundary
+// of b. Each edge of a is sampled at intervals of at most half the
+// tolerance. Requiring edges to run alongside each other stops the edges
+// leaving a shared corner from counting.
+func sharedLength(a, b boundary, tolerance float64) float64 {
+	// only edges of b near a can be within tolerance of it.
+	var edges [][2]vertex
+	for i := range b.ring {
+		p, q := b.ring[i], b.ring[(i+1)%len(b.ring)]
+		if a.near(p, q, tolerance) {
+			edges = append(edges, [2]vertex{p, q})
+		}
+	}
+	if len(edges) == 0 {
+		return 0
+	}
+
+	step := tolerance / 2
+	if step <= 0 {
+		step = 1
+	}
+	var shared float64
+	for i := range a.ring {
+		p, q := a.ring[i], a.ring[(i+1)%len(a.ring)]
+		length := math.Hypot(q.x-p.x, q.y-p.y)
+		if length == 0 || !b.near(p, q, tolerance) {
+			continue
+		}
+		samples := int(math.Ceil(length / step))
+		piece := length / float64(samples)
+		for s := 0; s < samples; s++ {
+			// sample the middle of each piece of the edge.
+			t := (float64(s) + 0.5) / float64(samples)
+			v := vertex{p.x + t*(q.x-p.x), p.y + t*(q.y-p.y)}
+			for _, e := range edges {
+				ex, ey := e[1].x-e[0].x, e[1].y-e[0].y
+				dot := math.Abs((q.x-p.x)*ex + (q.y-p.y)*ey)
+				if dot >= math.Sqrt2/2*length*math.Hypot(ex, ey) && alongside(v, e[0], e[1], tolerance) {
+					shared += piece
+					break
+				}
+			}
+		}
+	}
+	return shared
+}
+
+// alongside reports whether v is within tolerance of the segment from p to
+// q and lies beside it rather than beyond either end.
+func alongside(v, p, q vertex, tolerance float64) bool {
+	dx, dy := q.x-p.x, q.y-p.y
+	l := dx*dx + dy*dy
+	if l == 0 {
+		return false
+	}
+	t := ((v.x-p.x)*dx + (v.y-p.y)*dy) / l
+	if t < 0 || t > 1 {
+		return false
+	}
+	return math.Hypot(v.x-(p.x+t*dx), v.y-(p.y+t*dy)) <= tolerance
+}
+
+// sortRefs orders refs by force then neighbourhood.
+func sortRefs(refs []NeighbourhoodRef) {
+	sort.Slice(refs, func(i, j int) bool { return refLess(refs[i], refs[j]) })
+}
+
+func (g *AdjacencyGraph) addEdge(a, b NeighbourhoodRef, shared float64) {
+	if g.edges[a] == nil {
+		g.edges[a] = make(map[NeighbourhoodRef]float64)
+	}
+	if g.edges[b] == nil {
+		g.edges[b] = make(map[NeighbourhoodRef]float64)
+	}
+	g.edges[a][b] = shared
+	g.edges[b][a] = shared
+}
+
+// Nodes returns every neighbourhood in the graph ordered by force and ID.
+func (g *AdjacencyGraph) Nodes() []NeighbourhoodRef {
+	return append([]NeighbourhoodRef(nil), g.nodes...)
+}
+
+// Edges returns every edge of the graph, each once, ordered by their
+// neighbourhoods.
+func (g *AdjacencyGraph) Edges() []AdjacencyEdge {
+	var edges []AdjacencyEdge
+	for _, a := range g.nodes {
+		for _, b := range g.Neighbours(a) {
+			if refLess(a, b) {
+				edges = append(edges, AdjacencyEdge{A: a, B: b, SharedLength: g.edges[a][b]})
+			}
+		}
+	}
+	return edges
+}
+
+func refLess(a, b NeighbourhoodRef) bool {
+	if a.Force != b.Force {
+		return a.Force < b.Force
+	}
+	return a.Neighbourhood < b.Neighbourhood
+}
+
+// Neighbours returns the neighbourhoods sharing a boundary with ref ordered
+// by force and ID.
+func (g *AdjacencyGraph) Neighbours(ref NeighbourhoodRef) []NeighbourhoodRef {
+	var neighbours []NeighbourhoodRef
+	for n := range g.edges[ref] {
+		neighbours = append(neighbours, n)
+	}
+	sortRefs(neighbours)
+	return neighbours
+}
+
+// SharedLength returns the length in metres of the boundary shared by a and
+// b, or 0 if they are not adjacent.
+func (g *AdjacencyGraph) SharedLength(a, b NeighbourhoodRef) float64 {
+	return g.edges[a][b]
+}
+
+// KHop returns the neighbourhoods reachable from ref by crossing at most k
+// borders, excluding ref itself, ordered by hops then force and ID.
+func (g *AdjacencyGraph) KHop(ref NeighbourhoodRef, k int) []NeighbourhoodHop {
+	hops := map[NeighbourhoodRef]int{ref: 0}
+	frontier := []NeighbourhoodRef{ref}
+	var reached []NeighbourhoodHop
+	for depth := 1; depth <= k && len(frontier) > 0; depth++ {
+		var next []NeighbourhoodRef
+		for _, r := range frontier {
+			for _, n := range g.Neighbours(r) {
+				if _, seen := hops[n]; seen {
+					continue
+				}
+				hops[n] = depth
+				next = append(next, n)
+			}
+		}
+		sortRefs(next)
+		for _, n := range next {
+			reached = append(reached, NeighbourhoodHop{NeighbourhoodRef: n, Hops: depth})
+		}
+		frontier = next
+	}
+	return reached
+}
+
+// nodeID returns the identifier of a neighbourhood in exported graphs.
+func nodeID(ref NeighbourhoodRef) string {
+	return ref.Force + "/" + ref.Neighbourhood
+}
+
+// MarshalJSON implements the json.Marshaler interface, writing the nodes and
+// edges of the graph.
+func (g *AdjacencyGraph) MarshalJSON() ([]byte, error) {
+	type node struct {
+		ID string `json:"id"`
+		NeighbourhoodRef
+	}
+	type edge struct {
+		Source       string  `json:"source"`
+		Target       string  `json:"target"`
+		SharedLength float64 `json:"shared_length"`
+		CrossForce   bool    `json:"cross_force"`
+	}
+	out := struct {
+		Nodes []node `json:"nodes"`
+		Edges []edge `json:"edges"`
+	}{Nodes: []node{}, Edges: []edge{}}
+	for _, n := range g.nodes {
+		out.Nodes = append(out.Nodes, node{ID: nodeID(n), NeighbourhoodRef: n})
+	}
+	for _, e := range g.Edges() {
+		out.Edges = append(out.Edges, edge{
+			Source:       nodeID(e.A),
+			Target:       nodeID(e.B),
+			SharedLength: math.Round(e.SharedLength*10) / 10,
+			CrossForce:   e.CrossForce(),
+		})
+	}
+	return json.Marshal(out)
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// GraphML writes the graph to w in the GraphML format, with the force and
+// neighbourhood of each node and the shared length of each edge as data.
+func (g *AdjacencyGraph) GraphML(w io.Writer) error {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "force", For: "node", Name: "force", Type: "string"},
+			{ID: "neighbourhood", For: "node", Name: "neighbourhood", Type: "string"},
+			{ID: "shared_length", For: "edge", Name: "shared_length", Type: "double"},
+		},
+	}
+	doc.Graph.EdgeDefault = "undirected"
+	for _, n := range g.nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   nodeID(n),
+			Data: []graphMLData{{Key: "force", Value: n.Force}, {Key: "neighbourhood", Value: n.Neighbourhood}},
+		})
+	}
+	for _, e := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: nodeID(e.A),
+			Target: nodeID(e.B),
+			Data:   []graphMLData{{Key: "shared_length", Value: formatLength(e.SharedLength)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatLength formats a length in metres to one decimal place.
+func formatLength(f float64) string {
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,166 @@
+package ukpolice
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// square returns a square ring with sides of size metres whose south-west
+// corner is x metres east and y metres north of 52.6N 1.1W.
+func square(x, y, size float64) []Point {
+	pl := newPlanar(52.6)
+	x0, y0 := pl.project(Point{Latitude: 52.6, Longitude: -1.1})
+	return []Point{
+		pl.unproject(x0+x, y0+y),
+		pl.unproject(x0+x+size, y0+y),
+		pl.unproject(x0+x+size, y0+y+size),
+		pl.unproject(x0+x, y0+y+size),
+	}
+}
+
+func testAdjacencyGraph(t *testing.T) *AdjacencyGraph {
+	g, err := NewAdjacency().Graph(map[NeighbourhoodRef][]Point{
+		{Force: "leicestershire", Neighbourhood: "A"}: square(0, 0, 1000),
+		{Force: "leicestershire", Neighbourhood: "B"}: square(1000, 0, 1000),
+		// a 10m gap to C is within the tolerance.
+		{Force: "northamptonshire", Neighbourhood: "C"}: square(2010, 0, 1000),
+		// D touches A only at a corner.
+		{Force: "leicestershire", Neighbourhood: "D"}: square(-1000, 1000, 1000),
+		// E is well clear of everything.
+		{Force: "leicestershire", Neighbourhood: "E"}: square(5000, 5000, 1000),
+	})
+	if err != nil {
+		t.Fatalf("Adjacency.Graph returned error: %v", err)
+	}
+	return g
+}
+
+func TestAdjacencyGraph_Neighbours(t *testing.T) {
+	g := testAdjacencyGraph(t)
+	a := NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "A"}
+	b := NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "B"}
+	c := NeighbourhoodRef{Force: "northamptonshire", Neighbourhood: "C"}
+
+	if got := len(g.Nodes()); got != 5 {
+		t.Errorf("AdjacencyGraph.Nodes returned %d nodes, want 5", got)
+	}
+	if got, want := g.Neighbours(a), []NeighbourhoodRef{b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AdjacencyGraph.Neighbours(A) returned %v, want %v", got, want)
+	}
+	if got, want := g.Neighbours(b), []NeighbourhoodRef{a, c}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AdjacencyGraph.Neighbours(B) returned %v, want %v", got, want)
+	}
+	if got := g.Neighbours(NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "E"}); len(got) != 0 {
+		t.Errorf("AdjacencyGraph.Neighbours(E) returned %v, want none", got)
+	}
+	if got := g.SharedLength(a, b); math.Abs(got-1000) > 20 {
+		t.Errorf("AdjacencyGraph.SharedLength(A, B) is %v, want about 1000", got)
+	}
+
+	edges := g.Edges()
+	if len(edges) != 2 {
+		t.Fatalf("AdjacencyGraph.Edges returned %v, want 2 edges", edges)
+	}
+	if edges[0].CrossForce() || !edges[1].CrossForce() {
+		t.Errorf("AdjacencyGraph.Edges returned %v, want only the edge to C to cross forces", edges)
+	}
+}
+
+func TestAdjacency_GraphInvalid(t *testing.T) {
+	for _, a := range []*Adjacency{{Tolerance: -1}, {MinSharedLength: -1}, {Tolerance: math.NaN()}} {
+		boundaries := map[NeighbourhoodRef][]Point{{Force: "leicestershire", Neighbourhood: "A"}: square(0, 0, 1000)}
+		if _, err := a.Graph(boundaries); err == nil {
+			t.Errorf("Adjacency%+v.Graph should have returned an error", *a)
+		}
+	}
+}
+
+func TestAdjacencyGraph_KHop(t *testing.T) {
+	g := testAdjacencyGraph(t)
+	a := NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "A"}
+
+	want := []NeighbourhoodHop{
+		{NeighbourhoodRef: NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "B"}, Hops: 1},
+		{NeighbourhoodRef: NeighbourhoodRef{Force: "northamptonshire", Neighbourhood: "C"}, Hops: 2},
+	}
+	if got := g.KHop(a, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("AdjacencyGraph.KHop(A, 2) returned %v, want %v", got, want)
+	}
+	if got := g.KHop(a, 1); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("AdjacencyGraph.KHop(A, 1) returned %v, want %v", got, want[:1])
+	}
+	if got := g.KHop(a, 0); len(got) != 0 {
+		t.Errorf("AdjacencyGraph.KHop(A, 0) returned %v, want none", got)
+	}
+}
+
+func TestAdjacencyGraph_export(t *testing.T) {
+	g, err := NewAdjacency().Graph(map[NeighbourhoodRef][]Point{
+		{Force: "leicestershire", Neighbourhood: "A"}:   square(0, 0, 1000),
+		{Force: "northamptonshire", Neighbourhood: "B"}: square(1000, 0, 1000),
+	})
+	if err != nil {
+		t.Fatalf("Adjacency.Graph returned error: %v", err)
+	}
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	var got struct {
+		Nodes []struct {
+			ID    string `json:"id"`
+			Force string `json:"force"`
+		} `json:"nodes"`
+		Edges []struct {
+			Source     string `json:"source"`
+			Target     string `json:"target"`
+			CrossForce bool   `json:"cross_force"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].ID != "leicestershire/A" || got.Nodes[1].Force != "northamptonshire" {
+		t.Errorf("JSON nodes are %+v", got.Nodes)
+	}
+	if len(got.Edges) != 1 || got.Edges[0].Source != "leicestershire/A" ||
+		got.Edges[0].Target != "northamptonshire/B" || !got.Edges[0].CrossForce {
+		t.Errorf("JSON edges are %+v", got.Edges)
+	}
+
+	var buf bytes.Buffer
+	if err := g.GraphML(&buf); err != nil {
+		t.Fatalf("AdjacencyGraph.GraphML returned error: %v", err)
+	}
+	for _, want := range []string{
+		`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`,
+		`<graph edgedefault="undirected">`,
+		`<node id="leicestershire/A">`,
+		`<data key="force">northamptonshire</data>`,
+		`<edge source="leicestershire/A" target="northamptonshire/B">`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("AdjacencyGraph.GraphML output does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestBoundariesFromCrawls(t *testing.T) {
+	crawl := &ForceCrawl{Force: "leicestershire", Neighbourhoods: []NeighbourhoodDetails{
+		{Neighbourhood: Neighbourhood{ID: "NC04"}, Boundary: []Location{
+			{Latitude: "52.6", Longitude: "-1.1"},
+			{Latitude: "52.6", Longitude: "-1.2"},
+			{Latitude: "52.7", Longitude: "-1.2"},
+		}},
+		{Neighbourhood: Neighbourhood{ID: "NC66"}, Boundary: []Location{{Latitude: "52.6", Longitude: "-1.1"}}},
+	}}
+	got := BoundariesFromCrawls(crawl)
+	if len(got) != 1 || len(got[NeighbourhoodRef{Force: "leicestershire", Neighbourhood: "NC04"}]) != 3 {
+		t.Errorf("BoundariesFromCrawls returned %v", got)
+	}
+}
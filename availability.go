@@ -2,6 +2,8 @@ package ukpolice
 
 import (
 	"context"
+	"sort"
+	"sync"
 )
 
 // AvailabilityService handles communication with the availability related
@@ -39,3 +41,158 @@ func (a *AvailabilityService) GetAvailabilityInfo(ctx context.Context) ([]Availa
 
 	return availabilityInfo, resp, nil
 }
+
+// AvailabilityIndex answers questions about which months and forces have data
+// without scanning the raw availability information.
+type AvailabilityIndex struct {
+	// LastUpdated is the date the API was last updated when the index was
+	// built, if known.
+	LastUpdated string
+
+	months  []string
+	byForce map[string][]string
+	byMonth map[string]map[string]bool
+}
+
+// NewAvailabilityIndex builds an index from the information returned by
+// GetAvailabilityInfo.
+func NewAvailabilityIndex(info []AvailabilityInfo) *AvailabilityIndex {
+	idx := &AvailabilityIndex{
+		byForce: make(map[string][]string),
+		byMonth: make(map[string]map[string]bool),
+	}
+	for _, a := range info {
+		if _, ok := idx.byMonth[a.Date]; !ok {
+			idx.months = append(idx.months, a.Date)
+			idx.byMonth[a.Date] = make(map[string]bool)
+		}
+		for _, force := range a.StopAndSearch {
+			if !idx.byMonth[a.Date][force] {
+				idx.byMonth[a.Date][force] = true
+				idx.byForce[force] = append(idx.byForce[force], a.Date)
+			}
+		}
+	}
+
+	sort.Strings(idx.months)
+	for _, months := range idx.byForce {
+		sort.Strings(months)
+	}
+	return idx
+}
+
+// Months returns every month with street level crime data in ascending
+// order.
+func (idx *AvailabilityIndex) Months() []string {
+	return append([]string(nil), idx.months...)
+}
+
+// HasMonth reports whether street level crime data is available for month.
+func (idx *AvailabilityIndex) HasMonth(month string) bool {
+	_, ok := idx.byMonth[month]
+	return ok
+}
+
+// Earliest returns the earliest month with data, or an empty string if there
+// is none.
+func (idx *AvailabilityIndex) Earliest() string {
+	if len(idx.months) == 0 {
+		return ""
+	}
+	return idx.months[0]
+}
+
+// Latest returns the latest month with data, or an empty string if there is
+// none.
+func (idx *AvailabilityIndex) Latest() string {
+	if len(idx.months) == 0 {
+		return ""
+	}
+	return idx.months[len(idx.months)-1]
+}
+
+// HasStopAndSearch reports whether force has stop and search data for month.
+func (idx *AvailabilityIndex) HasStopAndSearch(force, month string) bool {
+	return idx.byMonth[month][force]
+}
+
+// Forces returns every force with stop and search data in ascending order.
+func (idx *AvailabilityIndex) Forces() []string {
+	forces := make([]string, 0, len(idx.byForce))
+	for force := range idx.byForce {
+		forces = append(forces, force)
+	}
+	sort.Strings(forces)
+	return forces
+}
+
+// MonthsFor returns the months in which force has stop and search data in
+// ascending order.
+func (idx *AvailabilityIndex) MonthsFor(force string) []string {
+	return append([]string(nil), idx.byForce[force]...)
+}
+
+// ForcesFor returns the forces with stop and search data for month in
+// ascending order.
+func (idx *AvailabilityIndex) ForcesFor(month string) []string {
+	var forces []string
+	for force := range idx.byMonth[month] {
+		forces = append(forces, force)
+	}
+	sort.Strings(forces)
+	return forces
+}
+
+// Gaps returns the months between the first and last month in which force has
+// stop and search data that are missing from its data, in ascending order.
+func (idx *AvailabilityIndex) Gaps(force string) []string {
+	months := idx.byForce[force]
+	if len(months) == 0 {
+		return nil
+	}
+
+	var gaps []string
+	all, _ := monthsBetween(months[0], months[len(months)-1])
+	for _, month := range all {
+		if !idx.byMonth[month][force] {
+			gaps = append(gaps, month)
+		}
+	}
+	return gaps
+}
+
+// availabilityCache holds the most recently built AvailabilityIndex.
+type availabilityCache struct {
+	mu    sync.Mutex
+	index *AvailabilityIndex
+}
+
+// GetAvailabilityIndex returns an index of data availability. The index is
+// cached on the client and rebuilt only when the date returned by
+// GetLastUpdated changes.
+func (a *AvailabilityService) GetAvailabilityIndex(ctx context.Context) (*AvailabilityIndex, error) {
+	updated, _, err := a.api.Crime.GetLastUpdated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var date string
+	if updated != nil {
+		date = updated.Date
+	}
+
+	cache := &a.api.availability
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.index != nil && cache.index.LastUpdated == date {
+		return cache.index, nil
+	}
+
+	info, _, err := a.GetAvailabilityInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := NewAvailabilityIndex(info)
+	idx.LastUpdated = date
+	cache.index = idx
+	return idx, nil
+}
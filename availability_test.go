@@ -43,3 +43,78 @@ func TestAvailabilityService_GetAvailabilityInfo(t *testing.T) {
 	}
 
 }
+
+func TestAvailabilityIndex(t *testing.T) {
+	idx := NewAvailabilityIndex([]AvailabilityInfo{
+		{"2015-06", []string{"bedfordshire", "cleveland", "durham"}},
+		{"2015-05", []string{"cleveland"}},
+		{"2015-04", []string{"bedfordshire", "cleveland"}},
+		{"2015-03", []string{"bedfordshire", "cleveland"}},
+		{"2015-02", nil},
+	})
+
+	if idx.Earliest() != "2015-02" || idx.Latest() != "2015-06" {
+		t.Errorf("AvailabilityIndex spans %v to %v, want 2015-02 to 2015-06", idx.Earliest(), idx.Latest())
+	}
+	if !idx.HasMonth("2015-02") || idx.HasMonth("2015-07") {
+		t.Errorf("AvailabilityIndex.HasMonth returned the wrong availability")
+	}
+	if !idx.HasStopAndSearch("durham", "2015-06") || idx.HasStopAndSearch("durham", "2015-05") {
+		t.Errorf("AvailabilityIndex.HasStopAndSearch returned the wrong availability")
+	}
+
+	if got, want := idx.MonthsFor("bedfordshire"), []string{"2015-03", "2015-04", "2015-06"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailabilityIndex.MonthsFor returned %v, want %v", got, want)
+	}
+	if got, want := idx.ForcesFor("2015-06"), []string{"bedfordshire", "cleveland", "durham"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailabilityIndex.ForcesFor returned %v, want %v", got, want)
+	}
+	if got, want := idx.Forces(), []string{"bedfordshire", "cleveland", "durham"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailabilityIndex.Forces returned %v, want %v", got, want)
+	}
+	if got, want := idx.Gaps("bedfordshire"), []string{"2015-05"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AvailabilityIndex.Gaps returned %v, want %v", got, want)
+	}
+	if got := idx.Gaps("cleveland"); got != nil {
+		t.Errorf("AvailabilityIndex.Gaps returned %v, want none", got)
+	}
+
+	empty := NewAvailabilityIndex(nil)
+	if empty.Earliest() != "" || empty.Latest() != "" || empty.Gaps("durham") != nil {
+		t.Errorf("empty AvailabilityIndex returned data")
+	}
+}
+
+func TestAvailabilityService_GetAvailabilityIndex(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	updated := "2015-07-01"
+	mux.HandleFunc("/crime-last-updated", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"date": %q}`, updated)
+	})
+	var requests int
+	mux.HandleFunc("/crimes-street-dates", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `[{"date": "2015-06", "stop-and-search": ["durham"]}]`)
+	})
+
+	idx, err := client.Availability.GetAvailabilityIndex(context.Background())
+	if err != nil {
+		t.Fatalf("Availability.GetAvailabilityIndex returned error: '%+v'", err)
+	}
+	if idx.LastUpdated != updated || !idx.HasStopAndSearch("durham", "2015-06") {
+		t.Errorf("Availability.GetAvailabilityIndex returned %v", idx)
+	}
+
+	cached, _ := client.Availability.GetAvailabilityIndex(context.Background())
+	if cached != idx || requests != 1 {
+		t.Errorf("Availability.GetAvailabilityIndex did not use the cached index")
+	}
+
+	updated = "2015-08-01"
+	refreshed, _ := client.Availability.GetAvailabilityIndex(context.Background())
+	if refreshed == idx || requests != 2 || refreshed.LastUpdated != updated {
+		t.Errorf("Availability.GetAvailabilityIndex did not refresh after an update")
+	}
+}
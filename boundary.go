@@ -0,0 +1,811 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Boundaries are requested as square tiles, as the API refuses requests for
+// areas holding more than 10,000 crimes with a 503 response. A tile refused
+// is split into quarters down to minTileSize metres.
+const (
+	tileSize    = 4000
+	minTileSize = 250
+	// tileMargin widens each tile requested so that results on its edges
+	// are not lost to the rounding of poly parameters.
+	tileMargin = 10
+)
+
+// Dissolve merges the boundaries of neighbourhoods into the boundary of their
+// force. Boundaries are rasterised onto a grid, so the small gaps and
+// overlaps between neighbouring boundaries are absorbed rather than becoming
+// slivers as they would in an exact polygon union. The value of NewDissolve
+// is used for any field that is not positive.
+type Dissolve struct {
+	// Resolution is the length of a grid cell edge in metres. It is increased
+	// where necessary to keep the grid within MaxCells.
+	Resolution float64
+	// Gap is the width in metres of the widest gap between boundaries that
+	// is closed.
+	Gap float64
+	// Tolerance is the greatest distance in metres the simplified boundary
+	// may stray from the rasterised one.
+	Tolerance float64
+	// MinArea is the area in square metres of the smallest polygon or hole
+	// kept. Smaller polygons are dropped and smaller holes filled.
+	MinArea float64
+	// MaxCells limits the size, and so the memory use, of the grid.
+	MaxCells int
+}
+
+// NewDissolve returns a Dissolve with 50m cells closing gaps of up to 100m,
+// simplified to within 50m and dropping polygons and holes smaller than
+// 0.1km².
+func NewDissolve() *Dissolve {
+	return &Dissolve{Resolution: 50, Gap: 100, Tolerance: 50, MinArea: 100000, MaxCells: 16000000}
+}
+
+// ForceBoundary is the boundary of a force, reconstructed from the boundaries
+// of its neighbourhoods.
+type ForceBoundary struct {
+	Force   string    `json:"force"`
+	Updated time.Time `json:"updated"`
+	// Polygons hold an exterior ring followed by any holes, largest first.
+	// Rings are open, with exteriors counter-clockwise and holes clockwise.
+	Polygons [][][]Point `json:"polygons"`
+}
+
+func (b ForceBoundary) String() string {
+	return Stringify(b)
+}
+
+// polygonContains reports whether p lies inside the exterior of polygon and
+// outside its holes.
+func polygonContains(p Point, polygon [][]Point) bool {
+	if len(polygon) == 0 || !pointInPolygon(p, polygon[0]) {
+		return false
+	}
+	for _, hole := range polygon[1:] {
+		if pointInPolygon(p, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether p lies inside the boundary.
+func (b *ForceBoundary) Contains(p Point) bool {
+	for _, polygon := range b.Polygons {
+		if polygonContains(p, polygon) {
+			return true
+		}
+	}
+	return false
+}
+
+// Area returns the area inside the boundary in square metres.
+func (b *ForceBoundary) Area() float64 {
+	var area float64
+	for _, polygon := range b.Polygons {
+		for i, ring := range polygon {
+			vs := make([]vertex, len(ring))
+			for k, p := range ring {
+				vs[k].x, vs[k].y = equalArea(p)
+			}
+			if i == 0 {
+				area += math.Abs(ringArea(vs))
+			} else {
+				area -= math.Abs(ringArea(vs))
+			}
+		}
+	}
+	return area
+}
+
+// GeoJSON returns the boundary as a GeoJSON feature collection holding a
+// single MultiPolygon feature.
+func (b *ForceBoundary) GeoJSON() ([]byte, error) {
+	return json.Marshal(NewFeatureCollection(Feature{
+		Type:       "Feature",
+		ID:         b.Force,
+		Geometry:   multiPolygonGeometry(b.Polygons),
+		Properties: map[string]interface{}{"force": b.Force, "area": math.Round(b.Area())},
+	}))
+}
+
+// PolygonOptions returns a WithPolygon option for each square tile of up to
+// 4km covering the boundary. Tiles lying wholly outside the exteriors of the
+// polygons are omitted, so the areas requested cover the boundary but extend
+// beyond it.
+func (b *ForceBoundary) PolygonOptions() []Option {
+	var opts []Option
+	for _, t := range b.tiles() {
+		opts = append(opts, t.option())
+	}
+	return opts
+}
+
+// tile is a square area of a boundary projected onto a plane.
+type tile struct {
+	pl       planar
+	min, max vertex
+}
+
+// tiles returns the tiles of tileSize covering the exteriors of the polygons
+// of b.
+func (b *ForceBoundary) tiles() []tile {
+	var sum float64
+	var n int
+	for _, polygon := range b.Polygons {
+		for _, p := range polygon[0] {
+			sum += p.Latitude
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	pl := newPlanar(sum / float64(n))
+	min, max := vertex{math.Inf(1), math.Inf(1)}, vertex{math.Inf(-1), math.Inf(-1)}
+	for _, polygon := range b.Polygons {
+		for _, p := range polygon[0] {
+			x, y := pl.project(p)
+			min = vertex{math.Min(min.x, x), math.Min(min.y, y)}
+			max = vertex{math.Max(max.x, x), math.Max(max.y, y)}
+		}
+	}
+
+	var tiles []tile
+	for y := min.y; y <= max.y; y += tileSize {
+		for x := min.x; x <= max.x; x += tileSize {
+			t := tile{pl: pl, min: vertex{x, y}, max: vertex{x + tileSize, y + tileSize}}
+			if b.overlaps(t) {
+				tiles = append(tiles, t)
+			}
+		}
+	}
+	return tiles
+}
+
+// overlaps reports whether t overlaps the exterior of any polygon of b.
+func (b *ForceBoundary) overlaps(t tile) bool {
+	for _, polygon := range b.Polygons {
+		ring := make([]vertex, len(polygon[0]))
+		for i, p := range polygon[0] {
+			ring[i].x, ring[i].y = t.pl.project(p)
+		}
+		if ringArea(clipRing(ring, t.min, t.max)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// clipRing clips ring to the rectangle from min to max with the
+// Sutherland-Hodgman algorithm.
+func clipRing(ring []vertex, min, max vertex) []vertex {
+	edges := []func(v vertex) float64{
+		func(v vertex) float64 { return v.x - min.x },
+		func(v vertex) float64 { return max.x - v.x },
+		func(v vertex) float64 { return v.y - min.y },
+		func(v vertex) float64 { return max.y - v.y },
+	}
+	for _, inside := range edges {
+		var clipped []vertex
+		for i := range ring {
+			p, q := ring[i], ring[(i+1)%len(ring)]
+			dp, dq := inside(p), inside(q)
+			if dp >= 0 {
+				clipped = append(clipped, p)
+			}
+			if (dp >= 0) != (dq >= 0) {
+				t := dp / (dp - dq)
+				clipped = append(clipped, vertex{p.x + t*(q.x-p.x), p.y + t*(q.y-p.y)})
+			}
+		}
+		ring = clipped
+	}
+	return ring
+}
+
+// contains reports whether p lies in t. Tiles include their lower edges and
+// exclude their upper ones, so each point lies in one tile.
+func (t tile) contains(p Point) bool {
+	x, y := t.pl.project(p)
+	return x >= t.min.x && x < t.max.x && y >= t.min.y && y < t.max.y
+}
+
+// option returns a WithPolygon option for t widened by tileMargin.
+func (t tile) option() Option {
+	min := vertex{t.min.x - tileMargin, t.min.y - tileMargin}
+	max := vertex{t.max.x + tileMargin, t.max.y + tileMargin}
+	return WithPolygon(polyParam([]Point{
+		t.pl.unproject(min.x, min.y),
+		t.pl.unproject(max.x, min.y),
+		t.pl.unproject(max.x, max.y),
+		t.pl.unproject(min.x, max.y),
+	}))
+}
+
+// quarters returns the quarters of t.
+func (t tile) quarters() []tile {
+	mid := vertex{(t.min.x + t.max.x) / 2, (t.min.y + t.max.y) / 2}
+	return []tile{
+		{t.pl, t.min, mid},
+		{t.pl, vertex{mid.x, t.min.y}, vertex{t.max.x, mid.y}},
+		{t.pl, vertex{t.min.x, mid.y}, vertex{mid.x, t.max.y}},
+		{t.pl, mid, t.max},
+	}
+}
+
+// query calls fetch with each tile of b and a function reporting whether a
+// result belongs to the tile and lies inside the boundary. A tile the API
+// refuses with a 503 response is split into quarters and fetched again, so
+// fetch must not keep any results when it returns an error.
+func (b *ForceBoundary) query(fetch func(poly Option, keep func(Point) bool) error) error {
+	var query func(t tile) error
+	query = func(t tile) error {
+		err := fetch(t.option(), func(p Point) bool { return t.contains(p) && b.Contains(p) })
+		if serr, ok := err.(*StatusError); !ok || serr.Response.StatusCode != http.StatusServiceUnavailable ||
+			t.max.x-t.min.x < 2*minTileSize {
+			return err
+		}
+		for _, q := range t.quarters() {
+			if !b.overlaps(q) {
+				continue
+			}
+			if err := query(q); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, t := range b.tiles() {
+		if err := query(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// polyParam formats ring as the value of a poly parameter.
+func polyParam(ring []Point) string {
+	parts := make([]string, len(ring))
+	for i, p := range ring {
+		parts[i] = strconv.FormatFloat(p.Latitude, 'f', 5, 64) + "," + strconv.FormatFloat(p.Longitude, 'f', 5, 64)
+	}
+	return strings.Join(parts, ":")
+}
+
+// Union returns the boundary of the union of the provided boundaries, which
+// are typically every neighbourhood of force. Rings with fewer than three
+// points are ignored.
+func (d *Dissolve) Union(force string, boundaries [][]Point) *ForceBoundary {
+	b := &ForceBoundary{Force: force, Updated: time.Now(), Polygons: [][][]Point{}}
+
+	var sum float64
+	var n int
+	for _, ring := range boundaries {
+		if len(ring) >= 3 {
+			for _, p := range ring {
+				sum += p.Latitude
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return b
+	}
+	pl := newPlanar(sum / float64(n))
+
+	var rings [][]vertex
+	min := vertex{math.Inf(1), math.Inf(1)}
+	max := vertex{math.Inf(-1), math.Inf(-1)}
+	for _, ring := range boundaries {
+		if len(ring) < 3 {
+			continue
+		}
+		vs := make([]vertex, len(ring))
+		for i, p := range ring {
+			x, y := pl.project(p)
+			vs[i] = vertex{x, y}
+			min = vertex{math.Min(min.x, x), math.Min(min.y, y)}
+			max = vertex{math.Max(max.x, x), math.Max(max.y, y)}
+		}
+		rings = append(rings, vs)
+	}
+
+	// size the grid with a margin wide enough for the closing pass.
+	d = d.withDefaults()
+	size := d.Resolution
+	var k, margin, w, h int
+	for {
+		k = int(math.Ceil(d.Gap / (2 * size)))
+		margin = k + 1
+		w = int(math.Ceil((max.x-min.x)/size)) + 2*margin
+		h = int(math.Ceil((max.y-min.y)/size)) + 2*margin
+		if w*h <= d.MaxCells {
+			break
+		}
+		size *= math.Sqrt(float64(w*h) / float64(d.MaxCells))
+	}
+	origin := vertex{min.x - float64(margin)*size, min.y - float64(margin)*size}
+
+	g := &raster{w: w, h: h, cells: make([]bool, w*h)}
+	for _, ring := range rings {
+		grid := make([]vertex, len(ring))
+		for i, v := range ring {
+			grid[i] = vertex{(v.x - origin.x) / size, (v.y - origin.y) / size}
+		}
+		g.fill(grid)
+	}
+	if k > 0 {
+		g.morph(k, true)
+		g.morph(k, false)
+	}
+
+	minArea := d.MinArea / (size * size)
+	for _, polygon := range outlinePolygons(g.trace(), minArea) {
+		var out [][]Point
+		for _, ring := range polygon {
+			ring = simplifyRing(ring, d.Tolerance/size)
+			if len(ring) < 3 {
+				if len(out) == 0 {
+					break
+				}
+				continue
+			}
+			points := make([]Point, len(ring))
+			for i, v := range ring {
+				points[i] = pl.unproject(origin.x+v.x*size, origin.y+v.y*size)
+			}
+			out = append(out, points)
+		}
+		if len(out) > 0 {
+			b.Polygons = append(b.Polygons, out)
+		}
+	}
+	return b
+}
+
+// withDefaults returns a copy of d with the values of NewDissolve in place of
+// fields that are not positive.
+func (d *Dissolve) withDefaults() *Dissolve {
+	c, def := *d, NewDissolve()
+	if !(c.Resolution > 0) {
+		c.Resolution = def.Resolution
+	}
+	if !(c.Gap > 0) {
+		c.Gap = def.Gap
+	}
+	if !(c.Tolerance > 0) {
+		c.Tolerance = def.Tolerance
+	}
+	if !(c.MinArea > 0) {
+		c.MinArea = def.MinArea
+	}
+	if c.MaxCells <= 0 {
+		c.MaxCells = def.MaxCells
+	}
+	return &c
+}
+
+// raster is a grid of cells, indexed from the south-west, that are either
+// inside or outside an area. Coordinates are in units of cells.
+type raster struct {
+	w, h  int
+	cells []bool
+}
+
+func (g *raster) at(i, j int) bool {
+	return i >= 0 && j >= 0 && i < g.w && j < g.h && g.cells[j*g.w+i]
+}
+
+// fill sets the cells whose centres lie inside ring by scanning each row
+// with the even-odd rule.
+func (g *raster) fill(ring []vertex) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range ring {
+		lo, hi = math.Min(lo, v.y), math.Max(hi, v.y)
+	}
+	var xs []float64
+	for j := maxInt(0, int(lo)); j < g.h && float64(j) <= hi; j++ {
+		y := float64(j) + 0.5
+		xs = xs[:0]
+		for a := range ring {
+			p, q := ring[a], ring[(a+1)%len(ring)]
+			if (p.y > y) != (q.y > y) {
+				xs = append(xs, p.x+(y-p.y)*(q.x-p.x)/(q.y-p.y))
+			}
+		}
+		sort.Float64s(xs)
+		for a := 0; a+1 < len(xs); a += 2 {
+			from := maxInt(0, int(math.Ceil(xs[a]-0.5)))
+			to := minInt(g.w-1, int(math.Floor(xs[a+1]-0.5)))
+			for i := from; i <= to; i++ {
+				g.cells[j*g.w+i] = true
+			}
+		}
+	}
+}
+
+// morph dilates, or erodes, the set cells by k cells in every direction.
+// Dilating then eroding closes gaps up to 2k cells wide.
+func (g *raster) morph(k int, dilate bool) {
+	g.cells = morphLines(g.cells, g.h, g.w, g.w, 1, k, dilate)
+	g.cells = morphLines(g.cells, g.w, g.h, 1, g.w, k, dilate)
+}
+
+// morphLines applies a dilation or erosion along each of n lines of length
+// cells, where line l starts at l*stride and steps by step. Cells beyond the
+// ends of a line count as unset.
+func morphLines(cells []bool, n, length, stride, step, k int, dilate bool) []bool {
+	out := make([]bool, len(cells))
+	count := make([]int, length+1)
+	for l := 0; l < n; l++ {
+		for a := 0; a < length; a++ {
+			count[a+1] = count[a]
+			if cells[l*stride+a*step] {
+				count[a+1]++
+			}
+		}
+		for a := 0; a < length; a++ {
+			set := count[minInt(length, a+k+1)] - count[maxInt(0, a-k)]
+			if dilate {
+				out[l*stride+a*step] = set > 0
+			} else {
+				out[l*stride+a*step] = set == 2*k+1
+			}
+		}
+	}
+	return out
+}
+
+// trace returns the outlines of the set cells with set cells on their left,
+// so exteriors run counter-clockwise and holes clockwise. Where two outlines
+// meet at a corner the trace turns left, keeping diagonally touching cells in
+// separate outlines.
+func (g *raster) trace() [][]vertex {
+	stride := g.w + 1
+	type edge struct {
+		from, to int
+		used     bool
+	}
+	var edges []edge
+	out := make(map[int][]int)
+	add := func(i0, j0, i1, j1 int) {
+		from, to := j0*stride+i0, j1*stride+i1
+		out[from] = append(out[from], len(edges))
+		edges = append(edges, edge{from: from, to: to})
+	}
+	for j := 0; j < g.h; j++ {
+		for i := 0; i < g.w; i++ {
+			if !g.at(i, j) {
+				continue
+			}
+			if !g.at(i, j-1) {
+				add(i, j, i+1, j)
+			}
+			if !g.at(i+1, j) {
+				add(i+1, j, i+1, j+1)
+			}
+			if !g.at(i, j+1) {
+				add(i+1, j+1, i, j+1)
+			}
+			if !g.at(i-1, j) {
+				add(i, j+1, i, j)
+			}
+		}
+	}
+
+	at := func(v int) vertex { return vertex{float64(v % stride), float64(v / stride)} }
+	var outlines [][]vertex
+	for start := range edges {
+		if edges[start].used {
+			continue
+		}
+		var ring []vertex
+		for e := start; ; {
+			edges[e].used = true
+			ring = append(ring, at(edges[e].from))
+
+			// prefer the leftmost turn at the end of the edge.
+			from, to := at(edges[e].from), at(edges[e].to)
+			dx, dy := to.x-from.x, to.y-from.y
+			next, best := -1, math.Inf(-1)
+			for _, c := range out[edges[e].to] {
+				v := at(edges[c].to)
+				if turn := dx*(v.y-to.y) - dy*(v.x-to.x); turn > best {
+					next, best = c, turn
+				}
+			}
+			if next == start || next < 0 || edges[next].used {
+				break
+			}
+			e = next
+		}
+		outlines = append(outlines, dropCollinear(ring))
+	}
+	return outlines
+}
+
+// dropCollinear removes the vertices of ring that lie on a straight line
+// between their neighbours.
+func dropCollinear(ring []vertex) []vertex {
+	var kept []vertex
+	for i, v := range ring {
+		p, q := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+		if (v.x-p.x)*(q.y-v.y)-(v.y-p.y)*(q.x-v.x) != 0 {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// ringArea returns the signed area of ring, positive if it runs
+// counter-clockwise.
+func ringArea(ring []vertex) float64 {
+	var area float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		area += p.x*q.y - q.x*p.y
+	}
+	return area / 2
+}
+
+// ringContains reports whether v lies inside ring using the even-odd rule.
+func ringContains(v vertex, ring []vertex) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.y > v.y) != (b.y > v.y) && v.x < (b.x-a.x)*(v.y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// outlinePolygons groups traced outlines into polygons of an exterior
+// followed by its holes, largest first, dropping exteriors and holes with
+// an area below minArea.
+func outlinePolygons(outlines [][]vertex, minArea float64) [][][]vertex {
+	type polygon struct {
+		area  float64
+		rings [][]vertex
+	}
+	var exteriors []*polygon
+	var holes [][]vertex
+	for _, ring := range outlines {
+		if len(ring) < 3 {
+			continue
+		}
+		switch area := ringArea(ring); {
+		case area >= minArea:
+			exteriors = append(exteriors, &polygon{area: area, rings: [][]vertex{ring}})
+		case area <= -minArea:
+			holes = append(holes, ring)
+		}
+	}
+	sort.SliceStable(exteriors, func(i, j int) bool { return exteriors[i].area > exteriors[j].area })
+
+	for _, hole := range holes {
+		// a point half way along the first edge of the hole lies on no other
+		// outline, so the innermost exterior around it holds the hole.
+		a, b := hole[0], hole[1]
+		d := math.Hypot(b.x-a.x, b.y-a.y)
+		probe := vertex{a.x + (b.x-a.x)/(2*d), a.y + (b.y-a.y)/(2*d)}
+		var owner *polygon
+		for _, e := range exteriors {
+			if (owner == nil || e.area < owner.area) && ringContains(probe, e.rings[0]) {
+				owner = e
+			}
+		}
+		if owner != nil {
+			owner.rings = append(owner.rings, hole)
+		}
+	}
+
+	polygons := make([][][]vertex, len(exteriors))
+	for i, e := range exteriors {
+		polygons[i] = e.rings
+	}
+	return polygons
+}
+
+// simplifyRing simplifies a closed ring with the Douglas-Peucker algorithm,
+// splitting it at the vertex furthest from the first.
+func simplifyRing(ring []vertex, tolerance float64) []vertex {
+	if len(ring) <= 3 {
+		return ring
+	}
+	far, best := 0, -1.0
+	for i, v := range ring {
+		if d := math.Hypot(v.x-ring[0].x, v.y-ring[0].y); d > best {
+			far, best = i, d
+		}
+	}
+	keep := make([]bool, len(ring))
+	keep[0], keep[far] = true, true
+	douglasPeucker(ring, 0, far, tolerance, keep)
+	douglasPeucker(ring, far, len(ring), tolerance, keep)
+
+	var simplified []vertex
+	for i, v := range ring {
+		if keep[i] {
+			simplified = append(simplified, v)
+		}
+	}
+	return simplified
+}
+
+// douglasPeucker marks the vertices between from and to, which may be
+// len(ring) to refer to the first vertex, that are kept.
+func douglasPeucker(ring []vertex, from, to int, tolerance float64, keep []bool) {
+	p, q := ring[from], ring[to%len(ring)]
+	far, best := -1, tolerance
+	for i := from + 1; i < to; i++ {
+		if d := segmentDistance(ring[i], p, q); d > best {
+			far, best = i, d
+		}
+	}
+	if far < 0 {
+		return
+	}
+	keep[far] = true
+	douglasPeucker(ring, from, far, tolerance, keep)
+	douglasPeucker(ring, far, to, tolerance, keep)
+}
+
+// segmentDistance returns the distance from v to the segment from p to q.
+func segmentDistance(v, p, q vertex) float64 {
+	dx, dy := q.x-p.x, q.y-p.y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((v.x-p.x)*dx+(v.y-p.y)*dy)/l))
+	}
+	return math.Hypot(v.x-(p.x+t*dx), v.y-(p.y+t*dy))
+}
+
+// Save writes the boundary to the file at path.
+func (b *ForceBoundary) Save(path string) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// LoadForceBoundary reads a boundary saved with Save.
+func LoadForceBoundary(path string) (*ForceBoundary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b := new(ForceBoundary)
+	if err := json.NewDecoder(f).Decode(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetForceBoundary fetches the boundary of every neighbourhood of force and
+// merges them with d, or with NewDissolve if d is nil. An error is returned
+// if any boundary cannot be fetched, as the result would be incomplete.
+func (n *NeighbourhoodService) GetForceBoundary(ctx context.Context, force string, d *Dissolve) (*ForceBoundary, error) {
+	crawl, err := n.CrawlForce(ctx, force, &CrawlOptions{Parts: []CrawlPart{CrawlBoundary}})
+	if err != nil {
+		return nil, err
+	}
+	if len(crawl.Errors) > 0 {
+		return nil, crawl.Errors[0]
+	}
+	if d == nil {
+		d = NewDissolve()
+	}
+	var rings [][]Point
+	for _, ring := range BoundariesFromCrawls(crawl) {
+		rings = append(rings, ring)
+	}
+	return d.Union(force, rings), nil
+}
+
+// CachedForceBoundary returns the boundary of force saved at path if it was
+// built less than maxAge ago. Otherwise, or if the file is missing or cannot
+// be decoded, it builds the boundary with
+// GetForceBoundary and saves it to path.
+func (n *NeighbourhoodService) CachedForceBoundary(ctx context.Context, force, path string, maxAge time.Duration) (*ForceBoundary, error) {
+	b, err := LoadForceBoundary(path)
+	if err == nil && b.Force == force && time.Since(b.Updated) < maxAge {
+		return b, nil
+	}
+	if err != nil && !cacheMiss(err) {
+		return nil, err
+	}
+
+	b, err = n.GetForceBoundary(ctx, force, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Save(path); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetStreetLevelCrimesInBoundary returns the street level crimes inside a
+// force boundary, requesting each tile of PolygonOptions in turn. Tiles the
+// API refuses as holding too many crimes are split and requested again.
+// Crimes outside the boundary are discarded. opts must not set a location.
+func (c *CrimeService) GetStreetLevelCrimesInBoundary(ctx context.Context, b *ForceBoundary, opts ...Option) ([]Crime, error) {
+	var crimes []Crime
+	err := b.query(func(poly Option, keep func(Point) bool) error {
+		found, _, err := c.GetStreetLevelCrimes(ctx, append([]Option{poly}, opts...)...)
+		if err != nil {
+			return err
+		}
+		for _, crime := range found {
+			if p, err := crime.Location.Point(); err == nil && keep(p) {
+				crimes = append(crimes, crime)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crimes, nil
+}
+
+// GetStreetLevelOutcomesInBoundary returns the street level outcomes inside a
+// force boundary as for GetStreetLevelCrimesInBoundary.
+func (c *CrimeService) GetStreetLevelOutcomesInBoundary(ctx context.Context, b *ForceBoundary, opts ...Option) ([]Outcome, error) {
+	var outcomes []Outcome
+	err := b.query(func(poly Option, keep func(Point) bool) error {
+		found, _, err := c.GetStreetLevelOutcomes(ctx, append([]Option{poly}, opts...)...)
+		if err != nil {
+			return err
+		}
+		for _, o := range found {
+			if p, err := o.Crime.Location.Point(); err == nil && keep(p) {
+				outcomes = append(outcomes, o)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// GetStopAndSearchesInBoundary returns the stop and searches inside a force
+// boundary as for GetStreetLevelCrimesInBoundary.
+func (s *StopAndSearchService) GetStopAndSearchesInBoundary(ctx context.Context, b *ForceBoundary, opts ...Option) ([]Search, error) {
+	var searches []Search
+	err := b.query(func(poly Option, keep func(Point) bool) error {
+		found, _, err := s.GetStopAndSearchesByArea(ctx, append([]Option{poly}, opts...)...)
+		if err != nil {
+			return err
+		}
+		for _, search := range found {
+			if p, err := search.Location.Point(); err == nil && keep(p) {
+				searches = append(searches, search)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
@@ -0,0 +1,279 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// offset returns the point x metres east and y metres north of 52.6N 1.1W.
+func offset(x, y float64) Point {
+	pl := newPlanar(52.6)
+	x0, y0 := pl.project(Point{Latitude: 52.6, Longitude: -1.1})
+	return pl.unproject(x0+x, y0+y)
+}
+
+func TestDissolve_Union(t *testing.T) {
+	b := NewDissolve().Union("leicestershire", [][]Point{
+		// two squares separated by a 40m gap, which is closed.
+		square(0, 0, 1000),
+		square(1040, 0, 1000),
+		// a ring of four rectangles around a 600m hole.
+		{offset(3000, 0), offset(4000, 0), offset(4000, 200), offset(3000, 200)},
+		{offset(3000, 800), offset(4000, 800), offset(4000, 1000), offset(3000, 1000)},
+		{offset(3000, 200), offset(3200, 200), offset(3200, 800), offset(3000, 800)},
+		{offset(3800, 200), offset(4000, 200), offset(4000, 800), offset(3800, 800)},
+		// a square with a 200m hole, which is too small to keep.
+		{offset(0, 3000), offset(1000, 3000), offset(1000, 3400), offset(0, 3400)},
+		{offset(0, 3600), offset(1000, 3600), offset(1000, 4000), offset(0, 4000)},
+		{offset(0, 3400), offset(400, 3400), offset(400, 3600), offset(0, 3600)},
+		{offset(600, 3400), offset(1000, 3400), offset(1000, 3600), offset(600, 3600)},
+		// a square too small to keep.
+		square(6000, 6000, 200),
+	})
+
+	if len(b.Polygons) != 3 {
+		t.Fatalf("Dissolve.Union returned %d polygons, want 3: %v", len(b.Polygons), b.Polygons)
+	}
+	if got := len(b.Polygons[0]); got != 1 || len(b.Polygons[0][0]) != 4 {
+		t.Errorf("first polygon is %v, want a rectangle", b.Polygons[0])
+	}
+	if got := len(b.Polygons[1]); got != 2 {
+		t.Errorf("second polygon has %d rings, want an exterior and a hole", got)
+	}
+	if got := len(b.Polygons[2]); got != 1 {
+		t.Errorf("third polygon has %d rings, want the small hole filled", got)
+	}
+
+	for _, tt := range []struct {
+		p    Point
+		want bool
+	}{
+		{offset(500, 500), true},
+		{offset(1020, 500), true},
+		{offset(3500, 100), true},
+		{offset(3500, 500), false},
+		{offset(500, 3500), true},
+		{offset(6100, 6100), false},
+		{offset(-100, 500), false},
+	} {
+		if got := b.Contains(tt.p); got != tt.want {
+			t.Errorf("ForceBoundary.Contains(%v) returned %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	// 2.04km² + 0.64km² + 1km².
+	if got := b.Area(); math.Abs(got-3.68e6)/3.68e6 > 0.02 {
+		t.Errorf("ForceBoundary.Area returned %v, want about 3.68e6", got)
+	}
+}
+
+func TestDissolve_UnionDefaults(t *testing.T) {
+	boundaries := [][]Point{square(0, 0, 1000), square(1040, 0, 1000), square(6000, 6000, 200)}
+	want := NewDissolve().Union("leicestershire", boundaries)
+	for _, d := range []*Dissolve{{}, {Resolution: -1, Gap: -1, Tolerance: -1, MinArea: -1, MaxCells: -1}} {
+		got := d.Union("leicestershire", boundaries)
+		if !reflect.DeepEqual(got.Polygons, want.Polygons) {
+			t.Errorf("Dissolve%+v.Union returned %v, want %v", *d, got.Polygons, want.Polygons)
+		}
+	}
+}
+
+func TestSimplifyRing(t *testing.T) {
+	// a staircase along the diagonal of a square.
+	ring := []vertex{{0, 0}, {4, 0}, {4, 1}, {3, 1}, {3, 2}, {2, 2}, {2, 3}, {1, 3}, {1, 4}, {0, 4}}
+	if got := simplifyRing(ring, 1); len(got) != 3 {
+		t.Errorf("simplifyRing returned %v, want a triangle", got)
+	}
+	if got := simplifyRing(ring, 0.1); len(got) != len(ring) {
+		t.Errorf("simplifyRing with a small tolerance returned %v, want every vertex", got)
+	}
+}
+
+func TestForceBoundary_GeoJSON(t *testing.T) {
+	b := NewDissolve().Union("leicestershire", [][]Point{square(0, 0, 1000)})
+	data, err := b.GeoJSON()
+	if err != nil {
+		t.Fatalf("ForceBoundary.GeoJSON returned error: %v", err)
+	}
+	var fc struct {
+		Features []struct {
+			ID       string `json:"id"`
+			Geometry struct {
+				Type        string           `json:"type"`
+				Coordinates [][][][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if len(fc.Features) != 1 || fc.Features[0].ID != "leicestershire" || fc.Features[0].Geometry.Type != "MultiPolygon" {
+		t.Fatalf("ForceBoundary.GeoJSON returned %s", data)
+	}
+	if ring := fc.Features[0].Geometry.Coordinates[0][0]; len(ring) != 5 || ring[0] != ring[4] {
+		t.Errorf("exterior ring is %v, want a closed rectangle", ring)
+	}
+}
+
+func TestForceBoundary_PolygonOptions(t *testing.T) {
+	b := NewDissolve().Union("leicestershire", [][]Point{square(0, 0, 1000), square(10000, 0, 1000)})
+	opts := b.PolygonOptions()
+	if len(opts) != 2 {
+		t.Fatalf("PolygonOptions returned %d options, want 2 skipping the tile between the squares", len(opts))
+	}
+	for _, opt := range opts {
+		v := url.Values{}
+		opt(&v)
+		if got := strings.Count(v.Get("poly"), ":"); got != 3 {
+			t.Errorf("PolygonOptions set poly %q, want a rectangle", v.Get("poly"))
+		}
+	}
+	if got := polyParam([]Point{{52.6, -1.1}, {52.61234567, -1.2}}); got != "52.60000,-1.10000:52.61235,-1.20000" {
+		t.Errorf("polyParam returned %q", got)
+	}
+}
+
+func TestCrimeService_GetStreetLevelCrimesInBoundary(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	b := NewDissolve().Union("leicestershire", [][]Point{square(0, 0, 1000), square(5000, 0, 1000)})
+	inside, outside := offset(500, 500), offset(2000, 500)
+
+	var polys []string
+	mux.HandleFunc("/crimes-street/all-crime", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("date"); got != "2017-01" {
+			t.Errorf("requested date %q, want 2017-01", got)
+		}
+		polys = append(polys, r.FormValue("poly"))
+		fmt.Fprintf(w, `[
+			{"id": 1, "location": {"latitude": "%f", "longitude": "%f"}},
+			{"id": 2, "location": {"latitude": "%f", "longitude": "%f"}}
+		]`, inside.Latitude, inside.Longitude, outside.Latitude, outside.Longitude)
+	})
+
+	crimes, err := client.Crime.GetStreetLevelCrimesInBoundary(context.Background(), b, WithDate("2017-01"))
+	if err != nil {
+		t.Fatalf("Crime.GetStreetLevelCrimesInBoundary returned error: %v", err)
+	}
+	if len(polys) != 2 || strings.Count(polys[0], ":") != 3 {
+		t.Errorf("requested polygons %q, want two rectangles", polys)
+	}
+	if len(crimes) != 1 || crimes[0].ID != 1 {
+		t.Errorf("Crime.GetStreetLevelCrimesInBoundary returned %v, want only crime 1", crimes)
+	}
+}
+
+func TestCrimeService_GetStreetLevelCrimesInBoundary_tooMany(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	b := NewDissolve().Union("leicestershire", [][]Point{square(0, 0, 3000)})
+	first, second := offset(500, 500), offset(2500, 2500)
+
+	requests := 0
+	mux.HandleFunc("/crimes-street/all-crime", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `[
+			{"id": 1, "location": {"latitude": "%f", "longitude": "%f"}},
+			{"id": 2, "location": {"latitude": "%f", "longitude": "%f"}}
+		]`, first.Latitude, first.Longitude, second.Latitude, second.Longitude)
+	})
+
+	crimes, err := client.Crime.GetStreetLevelCrimesInBoundary(context.Background(), b)
+	if err != nil {
+		t.Fatalf("Crime.GetStreetLevelCrimesInBoundary returned error: %v", err)
+	}
+	if requests != 5 {
+		t.Errorf("requested %d polygons, want the refused tile split into 4", requests)
+	}
+	if len(crimes) != 2 || crimes[0].ID == crimes[1].ID {
+		t.Errorf("Crime.GetStreetLevelCrimesInBoundary returned %v, want crimes 1 and 2 once each", crimes)
+	}
+}
+
+func TestCrimeService_GetStreetLevelCrimesInBoundary_unavailable(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	b := NewDissolve().Union("leicestershire", [][]Point{square(0, 0, 3000)})
+	mux.HandleFunc("/crimes-street/all-crime", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := client.Crime.GetStreetLevelCrimesInBoundary(context.Background(), b)
+	if serr, ok := err.(*StatusError); !ok || serr.Response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Crime.GetStreetLevelCrimesInBoundary returned error %v, want a 503 StatusError", err)
+	}
+}
+
+func TestNeighbourhoodService_CachedForceBoundary(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/leicestershire/neighbourhoods", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `[{"id": "NC04"}, {"id": "NC66"}]`)
+	})
+	for i, id := range []string{"NC04", "NC66"} {
+		ring := square(float64(i)*1000, 0, 1000)
+		mux.HandleFunc("/leicestershire/"+id+"/boundary", func(w http.ResponseWriter, r *http.Request) {
+			var points []string
+			for _, p := range ring {
+				points = append(points, fmt.Sprintf(`{"latitude": "%f", "longitude": "%f"}`, p.Latitude, p.Longitude))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(points, ","))
+		})
+	}
+
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "boundary.json")
+	for i := 0; i < 2; i++ {
+		b, err := client.Neighborhood.CachedForceBoundary(context.Background(), "leicestershire", path, time.Hour)
+		if err != nil {
+			t.Fatalf("Neighborhood.CachedForceBoundary returned error: %v", err)
+		}
+		if len(b.Polygons) != 1 || !b.Contains(offset(1500, 500)) {
+			t.Errorf("Neighborhood.CachedForceBoundary returned %v", b)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("neighbourhoods were requested %d times, want 1", requests)
+	}
+
+	for _, data := range []string{"", `{"force": "leicestershire", "polyg`} {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, err := client.Neighborhood.CachedForceBoundary(context.Background(), "leicestershire", path, time.Hour)
+		if err != nil {
+			t.Fatalf("Neighborhood.CachedForceBoundary with cache %q returned error: %v", data, err)
+		}
+		if len(b.Polygons) != 1 {
+			t.Errorf("Neighborhood.CachedForceBoundary with cache %q returned %v", data, b)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("neighbourhoods were requested %d times, want corrupt caches rebuilt", requests)
+	}
+}
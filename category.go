@@ -0,0 +1,134 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+)
+
+// CategoryLevel selects how finely crime categories are harmonised.
+type CategoryLevel int
+
+const (
+	// ModernCategory maps renamed historical categories onto their current
+	// name. Categories that were split into several current categories keep
+	// their historical name as they have no single modern equivalent.
+	ModernCategory CategoryLevel = iota
+	// RollUpCategory maps every category onto a coarser group that is
+	// comparable across the whole history of the API.
+	RollUpCategory
+)
+
+// CategoryMapping holds a versioned table for harmonising the crime category
+// URLs returned by the API across changes to its taxonomy.
+type CategoryMapping struct {
+	Version string `json:"version"`
+	// Modern maps historical category URLs onto current ones. Categories not
+	// listed are already current.
+	Modern map[string]string `json:"modern"`
+	// RollUp maps categories at the modern level onto roll-up groups.
+	RollUp map[string]string `json:"roll_up"`
+}
+
+// DefaultCategoryMapping reflects the changes made to the data.police.uk
+// taxonomy in September 2011, when criminal damage, drugs, theft and public
+// disorder were separated from other crime, and May 2013, when violent crime
+// became violence and sexual offences, public disorder and weapons was split
+// into public order and possession of weapons, and bicycle theft and theft
+// from the person were separated from other theft.
+//
+// Other crime before September 2011 includes offences that were later given
+// their own categories, so it is not comparable with later months even when
+// rolled up.
+var DefaultCategoryMapping = &CategoryMapping{
+	Version: "2013-05",
+	Modern: map[string]string{
+		"violent-crime": "violence-and-sexual-offences",
+	},
+	RollUp: map[string]string{
+		"anti-social-behaviour":        "anti-social-behaviour",
+		"bicycle-theft":                "theft",
+		"burglary":                     "burglary",
+		"criminal-damage-arson":        "criminal-damage-arson",
+		"drugs":                        "drugs",
+		"other-crime":                  "other-crime",
+		"other-theft":                  "theft",
+		"possession-of-weapons":        "public-order-and-weapons",
+		"public-disorder-weapons":      "public-order-and-weapons",
+		"public-order":                 "public-order-and-weapons",
+		"robbery":                      "robbery",
+		"shoplifting":                  "theft",
+		"theft-from-the-person":        "theft",
+		"vehicle-crime":                "vehicle-crime",
+		"violence-and-sexual-offences": "violence-and-sexual-offences",
+	},
+}
+
+// LoadCategoryMapping reads a CategoryMapping encoded as JSON.
+func LoadCategoryMapping(r io.Reader) (*CategoryMapping, error) {
+	var m CategoryMapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+	if m.Version == "" {
+		return nil, errors.New("category mapping has no version")
+	}
+	return &m, nil
+}
+
+// Harmonise returns the category at the provided level. Categories unknown to
+// the mapping, including AllCrime, are returned unchanged.
+func (m *CategoryMapping) Harmonise(category string, level CategoryLevel) string {
+	if modern, ok := m.Modern[category]; ok {
+		category = modern
+	}
+	if level == RollUpCategory {
+		if group, ok := m.RollUp[category]; ok {
+			return group
+		}
+	}
+	return category
+}
+
+// HarmoniseCrimes returns a copy of crimes with each category harmonised to
+// the provided level, ready to be aggregated.
+func (m *CategoryMapping) HarmoniseCrimes(crimes []Crime, level CategoryLevel) []Crime {
+	harmonised := make([]Crime, len(crimes))
+	for i, c := range crimes {
+		c.Category = m.Harmonise(c.Category, level)
+		harmonised[i] = c
+	}
+	return harmonised
+}
+
+// Categories returns the distinct categories at the provided level in
+// ascending order.
+func (m *CategoryMapping) Categories(level CategoryLevel) []string {
+	seen := make(map[string]bool)
+	for category := range m.RollUp {
+		seen[m.Harmonise(category, level)] = true
+	}
+	categories := make([]string, 0, len(seen))
+	for category := range seen {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Unmapped returns the URLs of the categories, as returned by
+// GetCrimeCategories, that the mapping does not know about. AllCrime is
+// ignored. A non-empty result means the mapping needs a new version.
+func (m *CategoryMapping) Unmapped(categories []CrimeCategory) []string {
+	var unmapped []string
+	for _, c := range categories {
+		if c.URL == AllCrime {
+			continue
+		}
+		if _, ok := m.RollUp[m.Harmonise(c.URL, ModernCategory)]; !ok {
+			unmapped = append(unmapped, c.URL)
+		}
+	}
+	return unmapped
+}
@@ -0,0 +1,96 @@
+package ukpolice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCategoryMapping_Harmonise(t *testing.T) {
+	tt := []struct {
+		category string
+		level    CategoryLevel
+		want     string
+	}{
+		{"violent-crime", ModernCategory, "violence-and-sexual-offences"},
+		{"violent-crime", RollUpCategory, "violence-and-sexual-offences"},
+		{"public-disorder-weapons", ModernCategory, "public-disorder-weapons"},
+		{"public-disorder-weapons", RollUpCategory, "public-order-and-weapons"},
+		{"possession-of-weapons", RollUpCategory, "public-order-and-weapons"},
+		{"bicycle-theft", ModernCategory, "bicycle-theft"},
+		{"bicycle-theft", RollUpCategory, "theft"},
+		{"something-new", RollUpCategory, "something-new"},
+		{AllCrime, RollUpCategory, AllCrime},
+	}
+	for _, tc := range tt {
+		if got := DefaultCategoryMapping.Harmonise(tc.category, tc.level); got != tc.want {
+			t.Errorf("Harmonise(%q, %v) returned %q, want %q", tc.category, tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestCategoryMapping_HarmoniseCrimes(t *testing.T) {
+	crimes := []Crime{
+		{Category: "violent-crime", Month: "2013-03"},
+		{Category: "violence-and-sexual-offences", Month: "2013-06"},
+		{Category: "public-disorder-weapons", Month: "2013-03"},
+		{Category: "public-order", Month: "2013-06"},
+		{Category: "possession-of-weapons", Month: "2013-06"},
+	}
+
+	harmonised := DefaultCategoryMapping.HarmoniseCrimes(crimes, RollUpCategory)
+	if crimes[0].Category != "violent-crime" {
+		t.Errorf("HarmoniseCrimes modified the provided crimes")
+	}
+
+	counts := MonthlyCrimeCounts(harmonised)
+	want := map[string][]SeriesPoint{
+		"violence-and-sexual-offences": {{"2013-03", 1}, {"2013-04", 0}, {"2013-05", 0}, {"2013-06", 1}},
+		"public-order-and-weapons":     {{"2013-03", 1}, {"2013-04", 0}, {"2013-05", 0}, {"2013-06", 2}},
+		AllCrime:                       {{"2013-03", 2}, {"2013-04", 0}, {"2013-05", 0}, {"2013-06", 3}},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("MonthlyCrimeCounts of harmonised crimes returned %v, want %v", counts, want)
+	}
+}
+
+func TestCategoryMapping_Categories(t *testing.T) {
+	want := []string{
+		"anti-social-behaviour", "burglary", "criminal-damage-arson", "drugs", "other-crime",
+		"public-order-and-weapons", "robbery", "theft", "vehicle-crime", "violence-and-sexual-offences",
+	}
+	if got := DefaultCategoryMapping.Categories(RollUpCategory); !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories returned %v, want %v", got, want)
+	}
+	if got := DefaultCategoryMapping.Categories(ModernCategory); len(got) != 15 {
+		t.Errorf("Categories returned %d modern categories, want 15", len(got))
+	}
+}
+
+func TestCategoryMapping_Unmapped(t *testing.T) {
+	categories := []CrimeCategory{
+		{URL: AllCrime}, {URL: "violent-crime"}, {URL: "burglary"}, {URL: "cyber-crime"},
+	}
+	want := []string{"cyber-crime"}
+	if got := DefaultCategoryMapping.Unmapped(categories); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmapped returned %v, want %v", got, want)
+	}
+}
+
+func TestLoadCategoryMapping(t *testing.T) {
+	m, err := LoadCategoryMapping(strings.NewReader(`{
+		"version": "test",
+		"modern": {"old": "new"},
+		"roll_up": {"new": "group"}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadCategoryMapping returned error: '%s'", err)
+	}
+	if got := m.Harmonise("old", RollUpCategory); got != "group" {
+		t.Errorf("Harmonise returned %q, want %q", got, "group")
+	}
+
+	if _, err := LoadCategoryMapping(strings.NewReader(`{}`)); err == nil {
+		t.Errorf("LoadCategoryMapping should have returned an error for a mapping with no version")
+	}
+}
@@ -0,0 +1,327 @@
+package ukpolice
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ContactKind is the kind of a contact detail.
+type ContactKind string
+
+// Kinds of contact details.
+const (
+	ContactEmail     ContactKind = "email"
+	ContactTelephone ContactKind = "telephone"
+	ContactMobile    ContactKind = "mobile"
+	ContactFax       ContactKind = "fax"
+	ContactWeb       ContactKind = "web"
+	ContactAddress   ContactKind = "address"
+	ContactSocial    ContactKind = "social"
+	ContactOther     ContactKind = "other"
+)
+
+// SocialPlatform is the social network of a social contact detail.
+type SocialPlatform string
+
+// Social platforms recognised in contact details and engagement methods.
+const (
+	// Twitter covers both twitter.com and x.com.
+	Twitter    SocialPlatform = "twitter"
+	Facebook   SocialPlatform = "facebook"
+	YouTube    SocialPlatform = "youtube"
+	Instagram  SocialPlatform = "instagram"
+	Flickr     SocialPlatform = "flickr"
+	LinkedIn   SocialPlatform = "linkedin"
+	TikTok     SocialPlatform = "tiktok"
+	Nextdoor   SocialPlatform = "nextdoor"
+	MySpace    SocialPlatform = "myspace"
+	Bebo       SocialPlatform = "bebo"
+	GooglePlus SocialPlatform = "google-plus"
+)
+
+// contactKeys maps the keys used by the API onto contact kinds and platforms.
+var contactKeys = map[string]struct {
+	kind     ContactKind
+	platform SocialPlatform
+}{
+	"email":       {ContactEmail, ""},
+	"telephone":   {ContactTelephone, ""},
+	"phone":       {ContactTelephone, ""},
+	"mobile":      {ContactMobile, ""},
+	"fax":         {ContactFax, ""},
+	"web":         {ContactWeb, ""},
+	"website":     {ContactWeb, ""},
+	"blog":        {ContactWeb, ""},
+	"forum":       {ContactWeb, ""},
+	"rss":         {ContactWeb, ""},
+	"e-messaging": {ContactWeb, ""},
+	"address":     {ContactAddress, ""},
+	"twitter":     {ContactSocial, Twitter},
+	"x":           {ContactSocial, Twitter},
+	"facebook":    {ContactSocial, Facebook},
+	"youtube":     {ContactSocial, YouTube},
+	"instagram":   {ContactSocial, Instagram},
+	"flickr":      {ContactSocial, Flickr},
+	"linkedin":    {ContactSocial, LinkedIn},
+	"tiktok":      {ContactSocial, TikTok},
+	"nextdoor":    {ContactSocial, Nextdoor},
+	"myspace":     {ContactSocial, MySpace},
+	"bebo":        {ContactSocial, Bebo},
+	"google-plus": {ContactSocial, GooglePlus},
+	"googleplus":  {ContactSocial, GooglePlus},
+}
+
+// platformHosts maps the hosts of social networks onto their platform.
+var platformHosts = map[string]SocialPlatform{
+	"twitter.com":     Twitter,
+	"x.com":           Twitter,
+	"facebook.com":    Facebook,
+	"fb.com":          Facebook,
+	"youtube.com":     YouTube,
+	"youtu.be":        YouTube,
+	"instagram.com":   Instagram,
+	"flickr.com":      Flickr,
+	"linkedin.com":    LinkedIn,
+	"tiktok.com":      TikTok,
+	"nextdoor.co.uk":  Nextdoor,
+	"nextdoor.com":    Nextdoor,
+	"myspace.com":     MySpace,
+	"bebo.com":        Bebo,
+	"plus.google.com": GooglePlus,
+}
+
+// genericPathSegments are skipped when extracting a handle from a URL.
+var genericPathSegments = map[string]bool{
+	"#!": true, "user": true, "c": true, "channel": true, "pages": true,
+	"people": true, "groups": true, "company": true, "in": true, "photos": true,
+}
+
+// Contact is a single parsed contact detail.
+type Contact struct {
+	Kind ContactKind `json:"kind"`
+	// Platform is set for social contacts.
+	Platform SocialPlatform `json:"platform,omitempty"`
+	// Key is the key or title the contact was given by the API.
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+	// Handle is the account name on a social platform, prefixed with @ on
+	// platforms that use it.
+	Handle string `json:"handle,omitempty"`
+	// URL is a link to the contact, e.g. a mailto or tel URL or the profile
+	// of a social account.
+	URL string `json:"url,omitempty"`
+}
+
+func (c Contact) String() string {
+	return Stringify(c)
+}
+
+// ParseContact parses a contact detail given its key, such as "email" or
+// "twitter", and value. Social contacts whose key is not recognised are
+// identified by the host of their URL.
+func ParseContact(key, value string) Contact {
+	value = strings.TrimSpace(value)
+	c := Contact{Kind: ContactOther, Key: key, Value: value}
+	if k, ok := contactKeys[strings.ToLower(strings.TrimSpace(key))]; ok {
+		c.Kind, c.Platform = k.kind, k.platform
+	} else if p := platformOf(value); p != "" {
+		c.Kind, c.Platform = ContactSocial, p
+	}
+
+	switch c.Kind {
+	case ContactEmail:
+		c.Value = strings.TrimPrefix(value, "mailto:")
+		c.URL = "mailto:" + c.Value
+	case ContactTelephone, ContactMobile, ContactFax:
+		c.Value = strings.TrimPrefix(value, "tel:")
+		c.URL = "tel:" + strings.Map(func(r rune) rune {
+			if r == '+' || r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, c.Value)
+	case ContactWeb:
+		c.URL = absoluteURL(value)
+	case ContactSocial:
+		c.Handle, c.URL = socialHandle(c.Platform, value)
+	case ContactOther:
+		if strings.Contains(value, "://") || strings.HasPrefix(value, "www.") {
+			c.URL = absoluteURL(value)
+		}
+	}
+	return c
+}
+
+// platformOf returns the social platform hosting the URL s, if any.
+func platformOf(s string) SocialPlatform {
+	u, err := url.Parse(absoluteURL(s))
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return platformHosts[host]
+}
+
+// absoluteURL adds a scheme to URLs written without one.
+func absoluteURL(s string) string {
+	if s == "" || strings.Contains(s, "://") {
+		return s
+	}
+	return "http://" + s
+}
+
+// socialHandle returns the handle and profile URL of a social account given
+// either its URL or its handle.
+func socialHandle(platform SocialPlatform, value string) (string, string) {
+	at := platform == Twitter || platform == Instagram || platform == TikTok
+	if value == "" {
+		return "", ""
+	}
+
+	if !strings.Contains(value, "/") {
+		name := strings.TrimPrefix(value, "@")
+		handle := name
+		if at {
+			handle = "@" + name
+		}
+		var profile string
+		switch platform {
+		case Twitter:
+			profile = "https://x.com/" + name
+		case Instagram:
+			profile = "https://www.instagram.com/" + name
+		case TikTok:
+			profile = "https://www.tiktok.com/@" + name
+		case Facebook:
+			profile = "https://www.facebook.com/" + name
+		}
+		return handle, profile
+	}
+
+	profile := absoluteURL(value)
+	u, err := url.Parse(profile)
+	if err != nil {
+		return "", value
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Fragment != "" {
+		// old Twitter URLs such as twitter.com/#!/name.
+		segments = append(segments, strings.Split(strings.Trim(u.Fragment, "!/"), "/")...)
+	}
+	for _, s := range segments {
+		if s == "" || genericPathSegments[strings.ToLower(s)] {
+			continue
+		}
+		name := strings.TrimPrefix(s, "@")
+		if at {
+			return "@" + name, profile
+		}
+		return name, profile
+	}
+	return "", profile
+}
+
+// ContactDetails holds the contact details of a force, neighbourhood or
+// officer keyed by the kind of contact, e.g. "email" or "twitter".
+type ContactDetails map[string]string
+
+// Contacts returns every contact detail parsed, ordered by key.
+func (c ContactDetails) Contacts() []Contact {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	contacts := make([]Contact, 0, len(keys))
+	for _, k := range keys {
+		if strings.TrimSpace(c[k]) == "" {
+			continue
+		}
+		contacts = append(contacts, ParseContact(k, c[k]))
+	}
+	return contacts
+}
+
+// OfKind returns the contact details of the provided kind, ordered by key.
+func (c ContactDetails) OfKind(kind ContactKind) []Contact {
+	var contacts []Contact
+	for _, contact := range c.Contacts() {
+		if contact.Kind == kind {
+			contacts = append(contacts, contact)
+		}
+	}
+	return contacts
+}
+
+// Email returns the email address, if any.
+func (c ContactDetails) Email() string {
+	if contacts := c.OfKind(ContactEmail); len(contacts) > 0 {
+		return contacts[0].Value
+	}
+	return ""
+}
+
+// Telephone returns the telephone number, if any.
+func (c ContactDetails) Telephone() string {
+	if contacts := c.OfKind(ContactTelephone); len(contacts) > 0 {
+		return contacts[0].Value
+	}
+	return ""
+}
+
+// Handle returns the handle of the account on the provided platform, if any.
+func (c ContactDetails) Handle(platform SocialPlatform) string {
+	for _, contact := range c.OfKind(ContactSocial) {
+		if contact.Platform == platform && contact.Handle != "" {
+			return contact.Handle
+		}
+	}
+	return ""
+}
+
+// Contact parses the engagement method using its title, or the host of its
+// URL if the title is not recognised.
+func (e EngagementMethods) Contact() Contact {
+	c := ParseContact(e.Title, e.URL)
+	if c.Kind == ContactOther {
+		c.URL = absoluteURL(e.URL)
+	}
+	return c
+}
+
+// Contacts returns the force's telephone number, website and engagement
+// methods parsed.
+func (f Force) Contacts() []Contact {
+	var contacts []Contact
+	if f.Telephone != "" {
+		contacts = append(contacts, ParseContact("telephone", f.Telephone))
+	}
+	if f.URL != "" {
+		contacts = append(contacts, ParseContact("web", f.URL))
+	}
+	for _, e := range f.Engagement {
+		contacts = append(contacts, e.Contact())
+	}
+	return contacts
+}
+
+// TeamHandles returns the distinct handles on the provided platform of the
+// members of neighbourhood teams, in the order first seen. Teams from several
+// neighbourhoods may be combined to collect the handles of a whole force.
+func TeamHandles(teams []NeighbourhoodTeam, platform SocialPlatform) []string {
+	seen := make(map[string]bool)
+	var handles []string
+	for _, member := range teams {
+		h := member.ContactDetails.Handle(platform)
+		key := strings.ToLower(h)
+		if h == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		handles = append(handles, h)
+	}
+	return handles
+}
@@ -0,0 +1,106 @@
+package ukpolice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseContact(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       Contact
+	}{
+		{"email", "mailto:team@example.police.uk", Contact{
+			Kind: ContactEmail, Key: "email", Value: "team@example.police.uk", URL: "mailto:team@example.police.uk"}},
+		{"telephone", "0116 222 2222", Contact{
+			Kind: ContactTelephone, Key: "telephone", Value: "0116 222 2222", URL: "tel:01162222222"}},
+		{"web", "www.leics.police.uk", Contact{
+			Kind: ContactWeb, Key: "web", Value: "www.leics.police.uk", URL: "http://www.leics.police.uk"}},
+		{"twitter", "http://www.twitter.com/ACCCLeicsPolice", Contact{
+			Kind: ContactSocial, Platform: Twitter, Key: "twitter", Value: "http://www.twitter.com/ACCCLeicsPolice",
+			Handle: "@ACCCLeicsPolice", URL: "http://www.twitter.com/ACCCLeicsPolice"}},
+		{"twitter", "http://twitter.com/#!/LeicsPolice", Contact{
+			Kind: ContactSocial, Platform: Twitter, Key: "twitter", Value: "http://twitter.com/#!/LeicsPolice",
+			Handle: "@LeicsPolice", URL: "http://twitter.com/#!/LeicsPolice"}},
+		{"x", "@LeicsPolice", Contact{
+			Kind: ContactSocial, Platform: Twitter, Key: "x", Value: "@LeicsPolice",
+			Handle: "@LeicsPolice", URL: "https://x.com/LeicsPolice"}},
+		{"youtube", "https://www.youtube.com/user/leicspolice", Contact{
+			Kind: ContactSocial, Platform: YouTube, Key: "youtube", Value: "https://www.youtube.com/user/leicspolice",
+			Handle: "leicspolice", URL: "https://www.youtube.com/user/leicspolice"}},
+		{"social", "https://www.instagram.com/leicspolice/", Contact{
+			Kind: ContactSocial, Platform: Instagram, Key: "social", Value: "https://www.instagram.com/leicspolice/",
+			Handle: "@leicspolice", URL: "https://www.instagram.com/leicspolice/"}},
+		{"beat-surgery", "Fridays at the library", Contact{
+			Kind: ContactOther, Key: "beat-surgery", Value: "Fridays at the library"}},
+	}
+	for _, tt := range tests {
+		if got := ParseContact(tt.key, tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseContact(%q, %q) returned %v, want %v", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestContactDetails(t *testing.T) {
+	c := ContactDetails{
+		"twitter":   "https://twitter.com/NWLPolice",
+		"email":     "nwl@leicestershire.pnn.police.uk",
+		"telephone": "101",
+		"facebook":  "",
+	}
+	if got := len(c.Contacts()); got != 3 {
+		t.Errorf("ContactDetails.Contacts returned %d contacts, want 3", got)
+	}
+	if got, want := c.Email(), "nwl@leicestershire.pnn.police.uk"; got != want {
+		t.Errorf("ContactDetails.Email returned %q, want %q", got, want)
+	}
+	if got, want := c.Telephone(), "101"; got != want {
+		t.Errorf("ContactDetails.Telephone returned %q, want %q", got, want)
+	}
+	if got, want := c.Handle(Twitter), "@NWLPolice"; got != want {
+		t.Errorf("ContactDetails.Handle returned %q, want %q", got, want)
+	}
+	if got := c.Handle(Facebook); got != "" {
+		t.Errorf("ContactDetails.Handle returned %q for an empty contact", got)
+	}
+}
+
+func TestForce_Contacts(t *testing.T) {
+	f := Force{
+		Telephone: "101",
+		URL:       "http://www.leics.police.uk/",
+		Engagement: []EngagementMethods{
+			{URL: "http://www.facebook.com/leicspolice", Title: "Facebook"},
+			{URL: "https://x.com/leicspolice", Title: "Follow us"},
+			{URL: "http://www.leics.police.uk/alerts", Title: "E-alerts"},
+		},
+	}
+	contacts := f.Contacts()
+	var kinds []ContactKind
+	for _, c := range contacts {
+		kinds = append(kinds, c.Kind)
+	}
+	want := []ContactKind{ContactTelephone, ContactWeb, ContactSocial, ContactSocial, ContactOther}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Force.Contacts returned kinds %v, want %v", kinds, want)
+	}
+	if contacts[3].Platform != Twitter || contacts[3].Handle != "@leicspolice" {
+		t.Errorf("Force.Contacts returned %v for an untitled X account", contacts[3])
+	}
+	if contacts[4].URL != "http://www.leics.police.uk/alerts" {
+		t.Errorf("Force.Contacts returned %v for an unrecognised engagement method", contacts[4])
+	}
+}
+
+func TestTeamHandles(t *testing.T) {
+	teams := []NeighbourhoodTeam{
+		{Name: "A", ContactDetails: ContactDetails{"twitter": "https://twitter.com/PCSmith"}},
+		{Name: "B", ContactDetails: ContactDetails{"email": "b@example.police.uk"}},
+		{Name: "C", ContactDetails: ContactDetails{"x": "@pcsmith"}},
+		{Name: "D", ContactDetails: ContactDetails{"twitter": "https://x.com/SgtJones"}},
+	}
+	want := []string{"@PCSmith", "@SgtJones"}
+	if got := TeamHandles(teams, Twitter); !reflect.DeepEqual(got, want) {
+		t.Errorf("TeamHandles returned %v, want %v", got, want)
+	}
+}
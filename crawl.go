@@ -0,0 +1,253 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// CrawlPart is one of the requests made for each neighbourhood in a crawl.
+type CrawlPart string
+
+// Parts of a neighbourhood crawl.
+const (
+	CrawlDetails    CrawlPart = "details"
+	CrawlBoundary   CrawlPart = "boundary"
+	CrawlTeam       CrawlPart = "team"
+	CrawlEvents     CrawlPart = "events"
+	CrawlPriorities CrawlPart = "priorities"
+)
+
+// CrawlParts holds every part of a neighbourhood crawl.
+var CrawlParts = []CrawlPart{CrawlDetails, CrawlBoundary, CrawlTeam, CrawlEvents, CrawlPriorities}
+
+// CrawlOptions configures CrawlForce. The zero value crawls every part with
+// four workers, retrying each failed request twice after 500ms and then 1s.
+// Only requests that may succeed later are retried: those answered with 429
+// Too Many Requests or a 5xx status, which may extend the wait with a
+// Retry-After header, and those that failed to reach the API.
+type CrawlOptions struct {
+	// Workers is the number of concurrent requests. Requests are still
+	// subject to the client's rate limit.
+	Workers int
+	// Retries is the number of times a failed request is retried. A negative
+	// value disables retries.
+	Retries int
+	// Backoff is the delay before the first retry, doubling for each retry
+	// after.
+	Backoff time.Duration
+	// Parts are the parts to request for each neighbourhood.
+	Parts []CrawlPart
+}
+
+// CrawlError records a request that failed after every retry. Err is the
+// error of the last attempt, such as a *StatusError.
+type CrawlError struct {
+	Neighbourhood string    `json:"neighbourhood"`
+	Part          CrawlPart `json:"part"`
+	Err           error     `json:"-"`
+}
+
+func (e *CrawlError) Error() string {
+	return fmt.Sprintf("neighbourhood %s: %s: %v", e.Neighbourhood, e.Part, e.Err)
+}
+
+// NeighbourhoodDetails holds everything the API returns for a neighbourhood.
+type NeighbourhoodDetails struct {
+	Neighbourhood Neighbourhood             `json:"neighbourhood"`
+	Boundary      []Location                `json:"boundary,omitempty"`
+	Team          []NeighbourhoodTeam       `json:"team,omitempty"`
+	Events        []NeighbourhoodEvent      `json:"events,omitempty"`
+	Priorities    []NeighbourhoodPriorities `json:"priorities,omitempty"`
+	// Errors holds the message of each part that could not be fetched.
+	Errors map[CrawlPart]string `json:"errors,omitempty"`
+}
+
+func (n NeighbourhoodDetails) String() string {
+	return Stringify(n)
+}
+
+// ForceCrawl holds the details of every neighbourhood of a force.
+type ForceCrawl struct {
+	Force          string                 `json:"force"`
+	Neighbourhoods []NeighbourhoodDetails `json:"neighbourhoods"`
+	// Errors holds every request that failed, ordered by neighbourhood and
+	// part.
+	Errors []*CrawlError `json:"-"`
+}
+
+func (f ForceCrawl) String() string {
+	return Stringify(f)
+}
+
+// Complete reports whether every request succeeded.
+func (f *ForceCrawl) Complete() bool {
+	return len(f.Errors) == 0
+}
+
+// CrawlForce fetches the neighbourhoods of force followed by the details,
+// boundary, team, events and priorities of each, making requests concurrently.
+// An error is returned only if the list of neighbourhoods cannot be fetched
+// or ctx is done; other failures are reported in the result.
+func (n *NeighbourhoodService) CrawlForce(ctx context.Context, force string, opts *CrawlOptions) (*ForceCrawl, error) {
+	o := CrawlOptions{Workers: 4, Retries: 2, Backoff: 500 * time.Millisecond, Parts: CrawlParts}
+	if opts != nil {
+		if opts.Workers > 0 {
+			o.Workers = opts.Workers
+		}
+		if opts.Retries != 0 {
+			o.Retries = opts.Retries
+		}
+		if opts.Backoff > 0 {
+			o.Backoff = opts.Backoff
+		}
+		if opts.Parts != nil {
+			o.Parts = opts.Parts
+		}
+	}
+
+	var neighbourhoods []Neighbourhood
+	err := retry(ctx, o, func() error {
+		var err error
+		neighbourhoods, _, err = n.GetNeighbourhoods(ctx, force)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	crawl := &ForceCrawl{Force: force, Neighbourhoods: make([]NeighbourhoodDetails, len(neighbourhoods))}
+	type job struct {
+		i    int
+		part CrawlPart
+	}
+	jobs := make(chan job)
+	failures := make(map[job]error)
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for w := 0; w < o.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				d := &crawl.Neighbourhoods[j.i]
+				err := retry(ctx, o, func() error {
+					return n.crawlPart(ctx, force, neighbourhoods[j.i].ID, d, j.part)
+				})
+				if err == nil {
+					continue
+				}
+				mu.Lock()
+				if d.Errors == nil {
+					d.Errors = make(map[CrawlPart]string)
+				}
+				d.Errors[j.part] = err.Error()
+				failures[j] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i, nb := range neighbourhoods {
+		crawl.Neighbourhoods[i].Neighbourhood = nb
+	}
+	for i := range neighbourhoods {
+		for _, part := range o.Parts {
+			select {
+			case jobs <- job{i: i, part: part}:
+			case <-ctx.Done():
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, d := range crawl.Neighbourhoods {
+		for _, part := range o.Parts {
+			if err, ok := failures[job{i: i, part: part}]; ok {
+				crawl.Errors = append(crawl.Errors, &CrawlError{
+					Neighbourhood: d.Neighbourhood.ID,
+					Part:          part,
+					Err:           err,
+				})
+			}
+		}
+	}
+	return crawl, nil
+}
+
+// crawlPart fetches one part of the neighbourhood with the provided ID into
+// d. Each part sets a different field, so parts of the same neighbourhood may
+// be fetched concurrently.
+func (n *NeighbourhoodService) crawlPart(ctx context.Context, force, id string, d *NeighbourhoodDetails, part CrawlPart) error {
+	var err error
+	switch part {
+	case CrawlDetails:
+		var nb *Neighbourhood
+		nb, _, err = n.GetSpecificNeighbourhood(ctx, force, id)
+		if err == nil && nb != nil {
+			if nb.ID == "" {
+				nb.ID = id
+			}
+			d.Neighbourhood = *nb
+		}
+	case CrawlBoundary:
+		d.Boundary, _, err = n.GetNeighbourhoodBoundary(ctx, force, id)
+	case CrawlTeam:
+		d.Team, _, err = n.GetNeighbourhoodTeam(ctx, force, id)
+	case CrawlEvents:
+		d.Events, _, err = n.GetNeighbourhoodEvents(ctx, force, id)
+	case CrawlPriorities:
+		d.Priorities, _, err = n.GetNeighbourhoodPriorities(ctx, force, id)
+	default:
+		err = fmt.Errorf("unknown crawl part %q", part)
+	}
+	return err
+}
+
+// retry calls f until it succeeds, fails with an error that is not worth
+// retrying, it has been retried o.Retries times or ctx is done. It waits
+// o.Backoff before the first retry and doubles the wait for each retry after,
+// waiting longer if the API asks it to.
+func retry(ctx context.Context, o CrawlOptions, f func() error) error {
+	wait := o.Backoff
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= o.Retries || ctx.Err() != nil {
+			return err
+		}
+		ok, after := retryable(err)
+		if !ok {
+			return err
+		}
+		delay := wait
+		if after > delay {
+			delay = after
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wait *= 2
+	}
+}
+
+// retryable reports whether a request that failed with err may succeed if it
+// is repeated, and the delay the API asked for before it is.
+func retryable(err error) (bool, time.Duration) {
+	switch e := err.(type) {
+	case *StatusError:
+		return e.Temporary(), e.RetryAfter
+	case net.Error:
+		return true, 0
+	}
+	return false, 0
+}
@@ -0,0 +1,166 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNeighbourhoodService_CrawlForce(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/leicestershire/neighbourhoods", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "NC04", "name": "City Centre"}, {"id": "NC66", "name": "Cultural Quarter"}]`)
+	})
+	for _, id := range []string{"NC04", "NC66"} {
+		id := id
+		mux.HandleFunc("/leicestershire/"+id, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"id": %q, "name": "Neighbourhood %s", "population": "7985"}`, id, id)
+		})
+		mux.HandleFunc("/leicestershire/"+id+"/boundary", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"latitude": "52.6394052587", "longitude": "-1.1458618876"}]`)
+		})
+		mux.HandleFunc("/leicestershire/"+id+"/people", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"name": "PC Smith", "rank": "PC"}]`)
+		})
+		mux.HandleFunc("/leicestershire/"+id+"/events", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+	}
+
+	// priorities fail once for NC04 and always for NC66.
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	for _, id := range []string{"NC04", "NC66"} {
+		id := id
+		mux.HandleFunc("/leicestershire/"+id+"/priorities", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			attempts[id]++
+			n := attempts[id]
+			mu.Unlock()
+			if id == "NC66" || n == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `<html>error</html>`)
+				return
+			}
+			fmt.Fprint(w, `[{"issue": "Parking", "action": "Patrols"}]`)
+		})
+	}
+
+	crawl, err := client.Neighborhood.CrawlForce(context.Background(), "leicestershire",
+		&CrawlOptions{Workers: 3, Retries: 2, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Neighborhood.CrawlForce returned error: '%s'", err)
+	}
+
+	if len(crawl.Neighbourhoods) != 2 {
+		t.Fatalf("Neighborhood.CrawlForce returned %d neighbourhoods, want 2", len(crawl.Neighbourhoods))
+	}
+	first := crawl.Neighbourhoods[0]
+	want := NeighbourhoodDetails{
+		Neighbourhood: Neighbourhood{ID: "NC04", Name: "Neighbourhood NC04", Population: "7985"},
+		Boundary:      []Location{{Latitude: "52.6394052587", Longitude: "-1.1458618876"}},
+		Team:          []NeighbourhoodTeam{{Name: "PC Smith", Rank: "PC"}},
+		Events:        []NeighbourhoodEvent{},
+		Priorities:    []NeighbourhoodPriorities{{Issue: "Parking", Action: "Patrols"}},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Neighborhood.CrawlForce returned %v, want %v", first, want)
+	}
+
+	if crawl.Complete() || len(crawl.Errors) != 1 {
+		t.Fatalf("ForceCrawl.Errors is %v, want one error", crawl.Errors)
+	}
+	if e := crawl.Errors[0]; e.Neighbourhood != "NC66" || e.Part != CrawlPriorities {
+		t.Errorf("ForceCrawl.Errors[0] is %v", e)
+	}
+	if _, ok := crawl.Neighbourhoods[1].Errors[CrawlPriorities]; !ok {
+		t.Errorf("NeighbourhoodDetails.Errors does not record the failed priorities")
+	}
+	if attempts["NC66"] != 3 {
+		t.Errorf("priorities were requested %d times, want 3", attempts["NC66"])
+	}
+}
+
+func TestNeighbourhoodService_CrawlForce_statuses(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/leicestershire/neighbourhoods", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "NC04"}]`)
+	})
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	count := func(r *http.Request) int {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[r.URL.Path]++
+		return attempts[r.URL.Path]
+	}
+	// details are missing, which is not retried.
+	mux.HandleFunc("/leicestershire/NC04", func(w http.ResponseWriter, r *http.Request) {
+		count(r)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	// the boundary is rate limited once.
+	mux.HandleFunc("/leicestershire/NC04/boundary", func(w http.ResponseWriter, r *http.Request) {
+		if count(r) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `[{"latitude": "52.6394052587", "longitude": "-1.1458618876"}]`)
+	})
+	// the team is unavailable twice with an empty body.
+	mux.HandleFunc("/leicestershire/NC04/people", func(w http.ResponseWriter, r *http.Request) {
+		if count(r) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `[{"name": "PC Smith"}]`)
+	})
+
+	crawl, err := client.Neighborhood.CrawlForce(context.Background(), "leicestershire",
+		&CrawlOptions{Retries: 2, Backoff: time.Millisecond, Parts: []CrawlPart{CrawlDetails, CrawlBoundary, CrawlTeam}})
+	if err != nil {
+		t.Fatalf("Neighborhood.CrawlForce returned error: '%s'", err)
+	}
+	if len(crawl.Errors) != 1 || crawl.Errors[0].Part != CrawlDetails {
+		t.Fatalf("ForceCrawl.Errors is %v, want only the details to fail", crawl.Errors)
+	}
+	if e, ok := crawl.Errors[0].Err.(*StatusError); !ok || e.Response.StatusCode != http.StatusNotFound {
+		t.Errorf("CrawlError.Err is %#v, want a *StatusError for 404", crawl.Errors[0].Err)
+	}
+	want := map[string]int{"/leicestershire/NC04": 1, "/leicestershire/NC04/boundary": 2, "/leicestershire/NC04/people": 3}
+	if !reflect.DeepEqual(attempts, want) {
+		t.Errorf("requests made are %v, want %v", attempts, want)
+	}
+	if d := crawl.Neighbourhoods[0]; len(d.Boundary) != 1 || len(d.Team) != 1 {
+		t.Errorf("Neighborhood.CrawlForce returned %v", d)
+	}
+}
+
+func TestNeighbourhoodService_CrawlForce_unknownForce(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+	client.Forces = testForceDirectory()
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Neighborhood.CrawlForce(context.Background(), "west-mids", &CrawlOptions{Backoff: time.Hour})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if _, ok := err.(*ForceError); !ok {
+			t.Errorf("Neighborhood.CrawlForce returned %v, want a *ForceError", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Neighborhood.CrawlForce retried an unknown force")
+	}
+}
@@ -67,6 +67,7 @@ func (c *CrimeService) GetStreetLevelCrimes(ctx context.Context, opts ...Option)
 	if err != nil {
 		return nil, nil, err
 	}
+	c.api.Streets.AddCrimes(crimes...)
 	return crimes, resp, nil
 }
 
@@ -86,6 +87,7 @@ func (c *CrimeService) GetStreetLevelOutcomes(ctx context.Context, opts ...Optio
 	if err != nil {
 		return nil, nil, err
 	}
+	c.api.Streets.AddOutcomes(outcomes...)
 	return outcomes, resp, nil
 }
 
@@ -106,6 +108,7 @@ func (c *CrimeService) GetCrimesAtLocation(ctx context.Context, opts ...Option)
 	if err != nil {
 		return nil, nil, err
 	}
+	c.api.Streets.AddCrimes(crimes...)
 
 	return crimes, resp, nil
 }
@@ -182,6 +185,9 @@ func (c *CrimeService) GetSpecificOutcomes(ctx context.Context, persistentID str
 	if err != nil {
 		return nil, nil, err
 	}
+	if crimeOutcomes != nil {
+		c.api.Streets.AddCrimes(crimeOutcomes.Crime)
+	}
 
 	return crimeOutcomes, resp, nil
 
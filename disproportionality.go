@@ -0,0 +1,220 @@
+package ukpolice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// EthnicPopulation holds the resident population of each ONS ethnic group.
+type EthnicPopulation map[EthnicGroup]float64
+
+// ReadEthnicPopulationCSV reads a population table with an ethnic group in
+// the first column and a population in the second, as found in census
+// downloads. A first row whose population is text, such as "Ethnic
+// group,Residents", is skipped as a header, rows for detailed groups such as "White:
+// Irish" are summed into their ONS group, and thousands separators are
+// ignored. Where a file holds both a row for an ONS group and rows for its
+// detailed groups, the group row is used so residents are not counted twice.
+// Rows whose group is not recognised, such as totals, are skipped.
+func ReadEthnicPopulationCSV(r io.Reader) (EthnicPopulation, error) {
+	totals := make(EthnicPopulation)
+	details := make(EthnicPopulation)
+	header := func(record []string) bool { return ParseEthnicGroup(record[0]) == "" }
+	err := readPopulationCSV(r, 2, header, func(record []string, n float64) {
+		group := ParseEthnicGroup(record[0])
+		switch {
+		case group == "":
+		case isDetailedEthnicGroup(record[0]):
+			details[group] += n
+		default:
+			totals[group] += n
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for group, n := range details {
+		if _, ok := totals[group]; !ok {
+			totals[group] = n
+		}
+	}
+	return totals, nil
+}
+
+// isDetailedEthnicGroup reports whether s names a detailed group within an
+// ONS group, such as "Indian", "White: Irish" or "Asian/Asian British -
+// Indian".
+func isDetailedEthnicGroup(s string) bool {
+	_, ok := ethnicSubgroups[enumKey(s)]
+	return ok || strings.Contains(s, ":") || strings.Contains(s, " - ")
+}
+
+// EthnicityBasis selects which recorded ethnicity is used for analysis.
+type EthnicityBasis int
+
+const (
+	// SelfDefined uses the ethnicity given by the person searched, which
+	// matches how census populations are recorded.
+	SelfDefined EthnicityBasis = iota
+	// OfficerDefined uses the ethnicity recorded by the officer.
+	OfficerDefined
+)
+
+// ethnicGroup returns the ONS ethnic group of the person searched, or an
+// empty group if it was not stated or is not recognised.
+func (s Search) ethnicGroup(basis EthnicityBasis) EthnicGroup {
+	if basis == OfficerDefined {
+		return ParseEthnicGroup(string(s.OfficerDefinedEthnicity))
+	}
+	return s.SelfDefinedEthnicity.Group()
+}
+
+// DisproportionalityOptions configures Disproportionality. The zero value
+// uses self-defined ethnicity, the White group as the baseline and 95%
+// confidence intervals.
+type DisproportionalityOptions struct {
+	Basis    EthnicityBasis
+	Baseline EthnicGroup
+	// Level is the coverage of confidence intervals, e.g. 0.95, and must
+	// lie between 0 and 1.
+	Level float64
+}
+
+// GroupRate holds the rate of stop and search for one ethnic group.
+type GroupRate struct {
+	Group      EthnicGroup `json:"group"`
+	Searches   int         `json:"searches"`
+	Population float64     `json:"population"`
+	// Rate is the number of searches per 1,000 residents.
+	Rate float64 `json:"rate"`
+	// Ratio is Rate divided by the rate of the baseline group. Lower and
+	// Upper bound its confidence interval.
+	Ratio float64 `json:"ratio"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+func (g GroupRate) String() string {
+	return Stringify(g)
+}
+
+// DisproportionalityReport holds search rates by ethnic group, overall and
+// for each legislation and object of search.
+type DisproportionalityReport struct {
+	Baseline EthnicGroup `json:"baseline"`
+	Groups   []GroupRate `json:"groups"`
+	// Unknown is the number of searches whose ethnicity was not stated or
+	// not recognised.
+	Unknown          int                            `json:"unknown"`
+	ByLegislation    map[Legislation][]GroupRate    `json:"by_legislation"`
+	ByObjectOfSearch map[ObjectOfSearch][]GroupRate `json:"by_object_of_search"`
+}
+
+func (d DisproportionalityReport) String() string {
+	return Stringify(d)
+}
+
+// Disproportionality compares the rate at which each ethnic group is searched
+// with the rate for the baseline group, using population as the number of
+// residents in each group. Groups missing from population are omitted.
+//
+// Confidence intervals for ratios use the log method for a ratio of Poisson
+// rates. Where a group or the baseline has no searches, 0.5 is added to both
+// counts so that a finite ratio and interval can be reported.
+func Disproportionality(searches []Search, population EthnicPopulation, opts *DisproportionalityOptions) (*DisproportionalityReport, error) {
+	o := DisproportionalityOptions{Baseline: EthnicGroupWhite, Level: 0.95}
+	if opts != nil {
+		o.Basis = opts.Basis
+		if opts.Baseline != "" {
+			o.Baseline = opts.Baseline
+		}
+		if opts.Level != 0 {
+			o.Level = opts.Level
+		}
+	}
+	if !(o.Level > 0 && o.Level < 1) {
+		return nil, errors.New("disproportionality interval level must lie between 0 and 1")
+	}
+	if population[o.Baseline] <= 0 {
+		return nil, fmt.Errorf("population has no residents in the baseline group %q", o.Baseline)
+	}
+
+	report := &DisproportionalityReport{
+		Baseline:         o.Baseline,
+		ByLegislation:    make(map[Legislation][]GroupRate),
+		ByObjectOfSearch: make(map[ObjectOfSearch][]GroupRate),
+	}
+
+	overall := make(map[EthnicGroup]int)
+	byLegislation := make(map[Legislation]map[EthnicGroup]int)
+	byObject := make(map[ObjectOfSearch]map[EthnicGroup]int)
+	for _, s := range searches {
+		group := s.ethnicGroup(o.Basis)
+		if group == "" {
+			report.Unknown++
+			continue
+		}
+		overall[group]++
+		if byLegislation[s.Legislation] == nil {
+			byLegislation[s.Legislation] = make(map[EthnicGroup]int)
+		}
+		byLegislation[s.Legislation][group]++
+		if byObject[s.ObjectOfSearch] == nil {
+			byObject[s.ObjectOfSearch] = make(map[EthnicGroup]int)
+		}
+		byObject[s.ObjectOfSearch][group]++
+	}
+
+	z := normalQuantile(0.5 + o.Level/2)
+	report.Groups = groupRates(overall, population, o.Baseline, z)
+	for l, counts := range byLegislation {
+		report.ByLegislation[l] = groupRates(counts, population, o.Baseline, z)
+	}
+	for obj, counts := range byObject {
+		report.ByObjectOfSearch[obj] = groupRates(counts, population, o.Baseline, z)
+	}
+	return report, nil
+}
+
+// groupRates computes the rate and ratio of each group with a population,
+// in ONS order.
+func groupRates(counts map[EthnicGroup]int, population EthnicPopulation, baseline EthnicGroup, z float64) []GroupRate {
+	base := float64(counts[baseline])
+	basePop := population[baseline]
+
+	var rates []GroupRate
+	for _, group := range EthnicGroups {
+		pop := population[group]
+		if pop <= 0 {
+			continue
+		}
+		n := float64(counts[group])
+		r := GroupRate{
+			Group:      group,
+			Searches:   counts[group],
+			Population: pop,
+			Rate:       1000 * n / pop,
+		}
+
+		if group == baseline {
+			r.Ratio, r.Lower, r.Upper = 1, 1, 1
+			rates = append(rates, r)
+			continue
+		}
+
+		a, b := n, base
+		if a == 0 || b == 0 {
+			a, b = a+0.5, b+0.5
+		}
+		r.Ratio = (a / pop) / (b / basePop)
+		se := math.Sqrt(1/a + 1/b)
+		r.Lower = r.Ratio * math.Exp(-z*se)
+		r.Upper = r.Ratio * math.Exp(z*se)
+		rates = append(rates, r)
+	}
+	return rates
+}
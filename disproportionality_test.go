@@ -0,0 +1,124 @@
+package ukpolice
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadEthnicPopulationCSV(t *testing.T) {
+	csv := `Ethnic group,Population
+"White: English, Welsh, Scottish, Northern Irish or British","1,000"
+White: Irish,500
+Black/African/Caribbean/Black British,"1,500"
+Asian/Asian British,250
+All usual residents,"3,250"
+`
+	population, err := ReadEthnicPopulationCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadEthnicPopulationCSV returned error: '%s'", err)
+	}
+	want := EthnicPopulation{EthnicGroupWhite: 1500, EthnicGroupBlack: 1500, EthnicGroupAsian: 250}
+	if !reflect.DeepEqual(population, want) {
+		t.Errorf("ReadEthnicPopulationCSV returned %v, want %v", population, want)
+	}
+
+	hierarchy := `White,"1,500"
+White: English,"1,000"
+White: Irish,500
+Asian/Asian British: Indian,250
+`
+	population, err = ReadEthnicPopulationCSV(strings.NewReader(hierarchy))
+	if err != nil {
+		t.Fatalf("ReadEthnicPopulationCSV returned error: '%s'", err)
+	}
+	want = EthnicPopulation{EthnicGroupWhite: 1500, EthnicGroupAsian: 250}
+	if !reflect.DeepEqual(population, want) {
+		t.Errorf("ReadEthnicPopulationCSV with group and detail rows returned %v, want %v", population, want)
+	}
+
+	for _, invalid := range []string{"White,100\nBlack,many\n", "White,1.2.3\nBlack,100\n", "White,many\nBlack,100\n"} {
+		if _, err := ReadEthnicPopulationCSV(strings.NewReader(invalid)); err == nil {
+			t.Errorf("ReadEthnicPopulationCSV(%q) should have returned an error for an invalid population", invalid)
+		}
+	}
+}
+
+func TestDisproportionality(t *testing.T) {
+	var searches []Search
+	add := func(n int, ethnicity SelfDefinedEthnicity, l Legislation) {
+		for i := 0; i < n; i++ {
+			searches = append(searches, Search{SelfDefinedEthnicity: ethnicity, Legislation: l, ObjectOfSearch: ControlledDrugs})
+		}
+	}
+	add(100, "White - English/Welsh/Scottish/Northern Irish/British", MisuseOfDrugsAct)
+	add(50, "Black/African/Caribbean/Black British - African", MisuseOfDrugsAct)
+	add(50, "Black/African/Caribbean/Black British - Caribbean", CriminalJusticeAndPublicOrderAct)
+	add(7, "Other ethnic group - Not stated", MisuseOfDrugsAct)
+
+	population := EthnicPopulation{EthnicGroupWhite: 100000, EthnicGroupBlack: 10000, EthnicGroupAsian: 20000}
+	report, err := Disproportionality(searches, population, nil)
+	if err != nil {
+		t.Fatalf("Disproportionality returned error: '%s'", err)
+	}
+
+	if report.Unknown != 7 || report.Baseline != EthnicGroupWhite || len(report.Groups) != 3 {
+		t.Fatalf("Disproportionality returned %v", report)
+	}
+
+	white, asian, black := report.Groups[0], report.Groups[1], report.Groups[2]
+	if white.Rate != 1 || white.Ratio != 1 || white.Lower != 1 {
+		t.Errorf("baseline group rate is %v", white)
+	}
+	if black.Searches != 100 || black.Rate != 10 || black.Ratio != 10 {
+		t.Errorf("black group rate is %v, want 10 per 1,000 and a ratio of 10", black)
+	}
+	// log method: exp(±1.96 * sqrt(1/100 + 1/100))
+	if math.Abs(black.Lower-7.58) > 0.01 || math.Abs(black.Upper-13.19) > 0.01 {
+		t.Errorf("black group interval is [%v, %v], want approximately [7.58, 13.19]", black.Lower, black.Upper)
+	}
+	if asian.Searches != 0 || asian.Ratio <= 0 || asian.Lower >= asian.Ratio || asian.Upper <= asian.Ratio {
+		t.Errorf("asian group rate is %v, want a corrected ratio with an interval", asian)
+	}
+
+	drugs := report.ByLegislation[MisuseOfDrugsAct]
+	if len(drugs) != 3 || drugs[2].Searches != 50 || drugs[2].Ratio != 5 {
+		t.Errorf("Misuse of Drugs Act rates are %v", drugs)
+	}
+	if s60 := report.ByLegislation[CriminalJusticeAndPublicOrderAct]; s60[0].Searches != 0 || s60[2].Searches != 50 {
+		t.Errorf("section 60 rates are %v", s60)
+	}
+	if object := report.ByObjectOfSearch[ControlledDrugs]; object[2].Searches != 100 {
+		t.Errorf("controlled drugs rates are %v", object)
+	}
+
+	officer, err := Disproportionality(searches, population, &DisproportionalityOptions{Basis: OfficerDefined})
+	if err != nil {
+		t.Fatalf("Disproportionality returned error: '%s'", err)
+	}
+	if officer.Unknown != len(searches) {
+		t.Errorf("officer-defined analysis found %d unknown ethnicities, want %d", officer.Unknown, len(searches))
+	}
+
+	officer, err = Disproportionality([]Search{
+		{OfficerDefinedEthnicity: "Black or Black British"},
+		{OfficerDefinedEthnicity: EthnicGroupWhite},
+		{OfficerDefinedEthnicity: "Not stated"},
+	}, population, &DisproportionalityOptions{Basis: OfficerDefined})
+	if err != nil {
+		t.Fatalf("Disproportionality returned error: '%s'", err)
+	}
+	if officer.Unknown != 1 || officer.Groups[2].Searches != 1 || officer.Groups[0].Searches != 1 {
+		t.Errorf("officer-defined analysis returned %v, want one white, one black and one unknown search", officer)
+	}
+
+	if _, err := Disproportionality(searches, EthnicPopulation{EthnicGroupBlack: 1}, nil); err == nil {
+		t.Errorf("Disproportionality should have returned an error without a baseline population")
+	}
+	for _, level := range []float64{-0.5, 1, 1.5, math.NaN()} {
+		if _, err := Disproportionality(searches, population, &DisproportionalityOptions{Level: level}); err == nil {
+			t.Errorf("Disproportionality should have returned an error for level %v", level)
+		}
+	}
+}
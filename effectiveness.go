@@ -0,0 +1,177 @@
+package ukpolice
+
+// Outcome IDs used by the API for arrests and no further action.
+const (
+	OutcomeArrest          = "bu-arrest"
+	OutcomeNoFurtherAction = "bu-no-further-action"
+)
+
+// outcomeNames maps the descriptions the API has used for outcomes, compared
+// with enumKey, to their outcome IDs.
+var outcomeNames = map[string]string{
+	enumKey("Arrest"):                            OutcomeArrest,
+	enumKey("Suspect arrested"):                  OutcomeArrest,
+	enumKey("A no further action disposal"):      OutcomeNoFurtherAction,
+	enumKey("Nothing found - no further action"): OutcomeNoFurtherAction,
+	enumKey("No further action"):                 OutcomeNoFurtherAction,
+}
+
+// outcomeID returns the ID of the outcome, or the ID of an outcome with the
+// same description if it has none.
+func (o SearchOutcome) outcomeID() string {
+	if o.ID != "" {
+		return o.ID
+	}
+	return outcomeNames[enumKey(o.Desc)]
+}
+
+// IsArrest reports whether the outcome was an arrest.
+func (o SearchOutcome) IsArrest() bool {
+	return o.outcomeID() == OutcomeArrest
+}
+
+// IsNoFurtherAction reports whether no further action was taken.
+func (o SearchOutcome) IsNoFurtherAction() bool {
+	return o.outcomeID() == OutcomeNoFurtherAction
+}
+
+// OutcomeMetrics counts the outcomes of a set of searches.
+type OutcomeMetrics struct {
+	Searches        int `json:"searches"`
+	Arrests         int `json:"arrests"`
+	NoFurtherAction int `json:"no_further_action"`
+	// LinkageRecorded is the number of searches for which the API states
+	// whether the outcome was linked to the object of search, and Linked the
+	// number for which it was.
+	LinkageRecorded int `json:"linkage_recorded"`
+	Linked          int `json:"linked"`
+}
+
+func (m OutcomeMetrics) String() string {
+	return Stringify(m)
+}
+
+func (m *OutcomeMetrics) add(s Search) {
+	m.Searches++
+	if s.Outcome.IsArrest() {
+		m.Arrests++
+	}
+	if s.Outcome.IsNoFurtherAction() {
+		m.NoFurtherAction++
+	}
+	if s.OutcomeLinkedToObject != nil {
+		m.LinkageRecorded++
+		if *s.OutcomeLinkedToObject {
+			m.Linked++
+		}
+	}
+}
+
+// ArrestRate returns the fraction of searches that led to an arrest.
+func (m OutcomeMetrics) ArrestRate() float64 {
+	return ratio(m.Arrests, m.Searches)
+}
+
+// NoFurtherActionRate returns the fraction of searches after which no further
+// action was taken.
+func (m OutcomeMetrics) NoFurtherActionRate() float64 {
+	return ratio(m.NoFurtherAction, m.Searches)
+}
+
+// FindRate returns the fraction of searches with a recorded linkage whose
+// outcome was linked to the object of search.
+func (m OutcomeMetrics) FindRate() float64 {
+	return ratio(m.Linked, m.LinkageRecorded)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// EffectivenessBreakdown holds outcome metrics overall and broken down by
+// the properties of searches. Months are in Europe/London local time and
+// force is taken from Search.Force.
+type EffectivenessBreakdown struct {
+	Overall          OutcomeMetrics                     `json:"overall"`
+	ByLegislation    map[Legislation]*OutcomeMetrics    `json:"by_legislation"`
+	ByObjectOfSearch map[ObjectOfSearch]*OutcomeMetrics `json:"by_object_of_search"`
+	ByType           map[SearchType]*OutcomeMetrics     `json:"by_type"`
+	ByForce          map[string]*OutcomeMetrics         `json:"by_force"`
+	ByMonth          map[string]*OutcomeMetrics         `json:"by_month"`
+}
+
+func newEffectivenessBreakdown() EffectivenessBreakdown {
+	return EffectivenessBreakdown{
+		ByLegislation:    make(map[Legislation]*OutcomeMetrics),
+		ByObjectOfSearch: make(map[ObjectOfSearch]*OutcomeMetrics),
+		ByType:           make(map[SearchType]*OutcomeMetrics),
+		ByForce:          make(map[string]*OutcomeMetrics),
+		ByMonth:          make(map[string]*OutcomeMetrics),
+	}
+}
+
+func (b *EffectivenessBreakdown) add(s Search) {
+	b.Overall.add(s)
+
+	if b.ByLegislation[s.Legislation] == nil {
+		b.ByLegislation[s.Legislation] = &OutcomeMetrics{}
+	}
+	b.ByLegislation[s.Legislation].add(s)
+
+	if b.ByObjectOfSearch[s.ObjectOfSearch] == nil {
+		b.ByObjectOfSearch[s.ObjectOfSearch] = &OutcomeMetrics{}
+	}
+	b.ByObjectOfSearch[s.ObjectOfSearch].add(s)
+
+	if b.ByType[s.Type] == nil {
+		b.ByType[s.Type] = &OutcomeMetrics{}
+	}
+	b.ByType[s.Type].add(s)
+
+	if b.ByForce[s.Force] == nil {
+		b.ByForce[s.Force] = &OutcomeMetrics{}
+	}
+	b.ByForce[s.Force].add(s)
+
+	if !s.DateTime.IsZero() {
+		month := s.DateTime.In(londonLocation).Format(monthLayout)
+		if b.ByMonth[month] == nil {
+			b.ByMonth[month] = &OutcomeMetrics{}
+		}
+		b.ByMonth[month].add(s)
+	}
+}
+
+// EffectivenessReport holds outcome metrics for a set of searches. Searches
+// involving the removal of more than outer clothing are sensitive, so they
+// are also reported on their own in StripSearches.
+type EffectivenessReport struct {
+	EffectivenessBreakdown
+	StripSearches EffectivenessBreakdown `json:"strip_searches"`
+}
+
+func (e EffectivenessReport) String() string {
+	return Stringify(e)
+}
+
+// Effectiveness computes arrest, no further action and find rates for the
+// searches matching every filter.
+func Effectiveness(searches []Search, filters ...SearchFilter) *EffectivenessReport {
+	report := &EffectivenessReport{
+		EffectivenessBreakdown: newEffectivenessBreakdown(),
+		StripSearches:          newEffectivenessBreakdown(),
+	}
+	for _, s := range searches {
+		if !matches(s, filters) {
+			continue
+		}
+		report.EffectivenessBreakdown.add(s)
+		if s.RemovalOfMoreThanOuterClothing {
+			report.StripSearches.add(s)
+		}
+	}
+	return report
+}
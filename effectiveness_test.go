@@ -0,0 +1,89 @@
+package ukpolice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchOutcome_Classification(t *testing.T) {
+	tt := []struct {
+		outcome SearchOutcome
+		arrest  bool
+		nfa     bool
+	}{
+		{SearchOutcome{ID: "bu-arrest", Desc: "Arrest", SearchHappened: true}, true, false},
+		{SearchOutcome{Desc: "Suspect arrested", SearchHappened: true}, true, false},
+		{SearchOutcome{ID: "bu-no-further-action", Desc: "A no further action disposal", SearchHappened: true}, false, true},
+		{SearchOutcome{Desc: "Nothing found - no further action", SearchHappened: true}, false, true},
+		{SearchOutcome{ID: "bu-caution", Desc: "Caution (simple or conditional)", SearchHappened: true}, false, false},
+		{SearchOutcome{Desc: "Not arrested - khat or cannabis warning", SearchHappened: true}, false, false},
+		{SearchOutcome{Desc: "Arrest warrant not executed - no further action needed?"}, false, false},
+		{SearchOutcome{}, false, false},
+	}
+	for _, tc := range tt {
+		if got := tc.outcome.IsArrest(); got != tc.arrest {
+			t.Errorf("IsArrest(%v) returned %v, want %v", tc.outcome, got, tc.arrest)
+		}
+		if got := tc.outcome.IsNoFurtherAction(); got != tc.nfa {
+			t.Errorf("IsNoFurtherAction(%v) returned %v, want %v", tc.outcome, got, tc.nfa)
+		}
+	}
+}
+
+func TestEffectiveness(t *testing.T) {
+	arrest := SearchOutcome{ID: OutcomeArrest, Desc: "Arrest", SearchHappened: true}
+	nfa := SearchOutcome{ID: OutcomeNoFurtherAction, Desc: "A no further action disposal", SearchHappened: true}
+	jan := time.Date(2017, 1, 10, 12, 0, 0, 0, time.UTC)
+	feb := time.Date(2017, 2, 10, 12, 0, 0, 0, time.UTC)
+
+	searches := []Search{
+		{Outcome: arrest, OutcomeLinkedToObject: Bool(true), Legislation: MisuseOfDrugsAct, ObjectOfSearch: ControlledDrugs, Type: PersonSearch, Force: "leicestershire", DateTime: jan},
+		{Outcome: nfa, OutcomeLinkedToObject: Bool(false), Legislation: MisuseOfDrugsAct, ObjectOfSearch: ControlledDrugs, Type: PersonSearch, Force: "leicestershire", DateTime: jan},
+		{Outcome: nfa, Legislation: MisuseOfDrugsAct, ObjectOfSearch: ControlledDrugs, Type: VehicleSearch, Force: "leicestershire", DateTime: feb},
+		{Outcome: arrest, OutcomeLinkedToObject: Bool(false), Legislation: CriminalJusticeAndPublicOrderAct, ObjectOfSearch: OffensiveWeapons, Type: PersonSearch, Force: "cleveland", DateTime: feb},
+		{Outcome: arrest, OutcomeLinkedToObject: Bool(true), Legislation: MisuseOfDrugsAct, RemovalOfMoreThanOuterClothing: true, Force: "cleveland", DateTime: feb},
+	}
+
+	report := Effectiveness(searches)
+	overall := report.Overall
+	want := OutcomeMetrics{Searches: 5, Arrests: 3, NoFurtherAction: 2, LinkageRecorded: 4, Linked: 2}
+	if overall != want {
+		t.Errorf("Effectiveness overall metrics are %v, want %v", overall, want)
+	}
+	if overall.Searches != len(searches) {
+		t.Errorf("Effectiveness counted %d searches, want %d", overall.Searches, len(searches))
+	}
+	if overall.ArrestRate() != 0.6 || overall.NoFurtherActionRate() != 0.4 || overall.FindRate() != 0.5 {
+		t.Errorf("Effectiveness rates are %v, %v, %v", overall.ArrestRate(), overall.NoFurtherActionRate(), overall.FindRate())
+	}
+
+	if m := report.ByLegislation[MisuseOfDrugsAct]; m.Searches != 4 || m.Arrests != 2 {
+		t.Errorf("Misuse of Drugs Act metrics are %v", m)
+	}
+	if m := report.ByObjectOfSearch[OffensiveWeapons]; m.Searches != 1 || m.FindRate() != 0 {
+		t.Errorf("offensive weapons metrics are %v", m)
+	}
+	if m := report.ByType[PersonSearch]; m.Searches != 3 {
+		t.Errorf("person search metrics are %v", m)
+	}
+	if m := report.ByForce["cleveland"]; m.Searches != 2 {
+		t.Errorf("cleveland metrics are %v, want strip searches included", m)
+	}
+	if m := report.ByMonth["2017-02"]; m.Searches != 3 {
+		t.Errorf("February metrics are %v", m)
+	}
+
+	strip := report.StripSearches.Overall
+	if strip.Searches != 1 || strip.Arrests != 1 || strip.FindRate() != 1 {
+		t.Errorf("strip search metrics are %v", strip)
+	}
+
+	filtered := Effectiveness(searches, LegislationFilter(CriminalJusticeAndPublicOrderAct))
+	if filtered.Overall.Searches != 1 || filtered.StripSearches.Overall.Searches != 0 {
+		t.Errorf("filtered metrics are %v", filtered)
+	}
+
+	if (OutcomeMetrics{}).FindRate() != 0 {
+		t.Errorf("FindRate of no searches should be 0")
+	}
+}
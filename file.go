@@ -0,0 +1,59 @@
+package ukpolice
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeFile writes b to the file at path, replacing any previous file
+// atomically so that readers never see a partial write.
+func writeFile(path string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".ukpolice-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// saveFile writes what encode produces to the file at path with writeFile.
+func saveFile(path string, encode func(io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		return err
+	}
+	return writeFile(path, buf.Bytes())
+}
+
+// loadFile opens the file at path and reads it with decode.
+func loadFile(path string, decode func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return decode(f)
+}
+
+// cacheMiss reports whether err, returned when loading a cache file, means
+// the file is missing or is corrupt or truncated and should be rebuilt.
+func cacheMiss(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError, *time.ParseError:
+		return true
+	}
+	return os.IsNotExist(err) || err == io.EOF || err == io.ErrUnexpectedEOF
+}
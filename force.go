@@ -32,10 +32,10 @@ func (f Force) String() string {
 
 // SeniorOfficer holds information on Senior Officers within a police force.
 type SeniorOfficer struct {
-	Bio            string            `json:"bio,omitempty"`
-	ContactDetails map[string]string `json:"contact_details,omitempty"`
-	Name           string            `json:"name,omitempty"`
-	Rank           string            `json:"rank,omitempty"`
+	Bio            string         `json:"bio,omitempty"`
+	ContactDetails ContactDetails `json:"contact_details,omitempty"`
+	Name           string         `json:"name,omitempty"`
+	Rank           string         `json:"rank,omitempty"`
 }
 
 func (so SeniorOfficer) String() string {
@@ -62,6 +62,13 @@ func (f *ForceService) GetForces(ctx context.Context) ([]Force, *Response, error
 
 // GetForceDetails returns more information about the provided force
 func (f *ForceService) GetForceDetails(ctx context.Context, force string) (Force, *Response, error) {
+	if err := f.api.Forces.Validate(force); err != nil {
+		return Force{}, nil, err
+	}
+	return f.getForceDetails(ctx, force)
+}
+
+func (f *ForceService) getForceDetails(ctx context.Context, force string) (Force, *Response, error) {
 	u := fmt.Sprintf("forces/%s", force)
 	forceDetails := Force{}
 	req, err := f.api.NewRequest("GET", u, nil)
@@ -80,6 +87,9 @@ func (f *ForceService) GetForceDetails(ctx context.Context, force string) (Force
 // GetPeople returns a slice containing details of the senior police officers
 // of the requested police force.
 func (f *ForceService) GetPeople(ctx context.Context, force string) ([]SeniorOfficer, *Response, error) {
+	if err := f.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("forces/%s/people", force)
 
 	req, err := f.api.NewRequest("GET", u, nil)
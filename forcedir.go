@@ -0,0 +1,433 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// forceAliases maps common abbreviations and informal names of forces onto
+// their IDs. Aliases are only used for forces present in a directory.
+var forceAliases = map[string]string{
+	"met":         "metropolitan",
+	"the met":     "metropolitan",
+	"mps":         "metropolitan",
+	"met police":  "metropolitan",
+	"gmp":         "greater-manchester",
+	"manchester":  "greater-manchester",
+	"wmp":         "west-midlands",
+	"west mids":   "west-midlands",
+	"btp":         "btp",
+	"transport":   "btp",
+	"col":         "city-of-london",
+	"city":        "city-of-london",
+	"psni":        "northern-ireland",
+	"ni":          "northern-ireland",
+	"tvp":         "thames-valley",
+	"asp":         "avon-and-somerset",
+	"avon":        "avon-and-somerset",
+	"somerset":    "avon-and-somerset",
+	"d&c":         "devon-and-cornwall",
+	"devon":       "devon-and-cornwall",
+	"cornwall":    "devon-and-cornwall",
+	"dyfed":       "dyfed-powys",
+	"powys":       "dyfed-powys",
+	"wyp":         "west-yorkshire",
+	"syp":         "south-yorkshire",
+	"nyp":         "north-yorkshire",
+	"mercia":      "west-mercia",
+	"liverpool":   "merseyside",
+	"birmingham":  "west-midlands",
+	"leeds":       "west-yorkshire",
+	"sheffield":   "south-yorkshire",
+	"newcastle":   "northumbria",
+	"cardiff":     "south-wales",
+	"swansea":     "south-wales",
+	"mids":        "west-midlands",
+	"west mid":    "west-midlands",
+	"hants":       "hampshire",
+	"beds":        "bedfordshire",
+	"cambs":       "cambridgeshire",
+	"herts":       "hertfordshire",
+	"lancs":       "lancashire",
+	"leics":       "leicestershire",
+	"lincs":       "lincolnshire",
+	"notts":       "nottinghamshire",
+	"staffs":      "staffordshire",
+	"wilts":       "wiltshire",
+	"glos":        "gloucestershire",
+	"bucks":       "thames-valley",
+	"oxford":      "thames-valley",
+	"nottingham":  "nottinghamshire",
+	"leicester":   "leicestershire",
+	"lincoln":     "lincolnshire",
+	"derby":       "derbyshire",
+	"northants":   "northamptonshire",
+	"north wales": "north-wales",
+}
+
+// forceStopWords are ignored when comparing names of forces.
+var forceStopWords = map[string]bool{
+	"police": true, "constabulary": true, "service": true, "force": true,
+	"the": true, "and": true, "of": true,
+}
+
+// ForceMatch is a force suggested for a name, with a score between 0 and 1
+// indicating how closely it matched.
+type ForceMatch struct {
+	Force Force   `json:"force"`
+	Score float64 `json:"score"`
+}
+
+func (m ForceMatch) String() string {
+	return Stringify(m)
+}
+
+// ForceError is returned when a name does not identify a single force.
+// Suggestions holds the closest forces, best first.
+type ForceError struct {
+	Name        string
+	Ambiguous   bool
+	Suggestions []ForceMatch
+}
+
+func (e *ForceError) Error() string {
+	msg := fmt.Sprintf("unknown force %q", e.Name)
+	if e.Ambiguous {
+		msg = fmt.Sprintf("ambiguous force %q", e.Name)
+	}
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	ids := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		ids[i] = fmt.Sprintf("%q", s.Force.ID)
+	}
+	return msg + "; did you mean " + strings.Join(ids, ", ") + "?"
+}
+
+// ForceDirectory holds the forces known to the API and resolves the names
+// people use for them to force IDs. It is safe for concurrent use.
+//
+// Assign a directory to Client.Forces to have the client reject unknown
+// force IDs before sending requests.
+type ForceDirectory struct {
+	mu      sync.RWMutex
+	updated time.Time
+	forces  map[string]Force
+}
+
+// forceDirectoryJSON is the format written by Save.
+type forceDirectoryJSON struct {
+	Updated time.Time `json:"updated"`
+	Forces  []Force   `json:"forces"`
+}
+
+// NewForceDirectory returns a directory of the provided forces.
+func NewForceDirectory(forces []Force) *ForceDirectory {
+	d := &ForceDirectory{updated: time.Now(), forces: make(map[string]Force, len(forces))}
+	for _, f := range forces {
+		d.forces[f.ID] = f
+	}
+	return d
+}
+
+// Updated returns when the directory was fetched from the API.
+func (d *ForceDirectory) Updated() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.updated
+}
+
+// Len returns the number of forces in the directory.
+func (d *ForceDirectory) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.forces)
+}
+
+// Force returns the force with the provided ID.
+func (d *ForceDirectory) Force(id string) (Force, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	f, ok := d.forces[id]
+	return f, ok
+}
+
+// Forces returns every force in the directory ordered by ID.
+func (d *ForceDirectory) Forces() []Force {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	forces := make([]Force, 0, len(d.forces))
+	for _, f := range d.forces {
+		forces = append(forces, f)
+	}
+	sort.Slice(forces, func(i, j int) bool { return forces[i].ID < forces[j].ID })
+	return forces
+}
+
+// Validate returns a *ForceError with suggestions if id is not the ID of a
+// force in the directory. A nil or empty directory accepts every ID.
+func (d *ForceDirectory) Validate(id string) error {
+	if d == nil || d.Len() == 0 {
+		return nil
+	}
+	if _, ok := d.Force(id); ok {
+		return nil
+	}
+	return &ForceError{Name: id, Suggestions: d.Suggest(id, 3)}
+}
+
+// Resolve returns the ID of the force named by name, which may be an ID, the
+// full or partial name of a force or a common abbreviation such as "met",
+// "gmp" or "west mids". Minor misspellings are tolerated. A *ForceError with
+// suggestions is returned if name does not clearly identify one force. Like
+// Validate, a nil or empty directory accepts name as given.
+func (d *ForceDirectory) Resolve(name string) (string, error) {
+	if d == nil || d.Len() == 0 {
+		return name, nil
+	}
+	if _, ok := d.Force(name); ok {
+		return name, nil
+	}
+
+	matches := d.Suggest(name, 5)
+	if len(matches) == 0 {
+		return "", &ForceError{Name: name}
+	}
+	best := matches[0]
+	switch {
+	case best.Score == 1 && (len(matches) == 1 || matches[1].Score < 1):
+		return best.Force.ID, nil
+	case best.Score >= 0.75 && (len(matches) == 1 || best.Score-matches[1].Score >= 0.1):
+		return best.Force.ID, nil
+	}
+
+	ambiguous := len(matches) > 1 && best.Score >= 0.75
+	if ambiguous {
+		// only suggest the forces competing for the match.
+		n := 1
+		for n < len(matches) && best.Score-matches[n].Score < 0.1 {
+			n++
+		}
+		matches = matches[:n]
+	}
+	return "", &ForceError{Name: name, Ambiguous: ambiguous, Suggestions: matches}
+}
+
+// Suggest returns up to n forces matching name, best first. Forces scoring
+// below 0.5 are omitted.
+func (d *ForceDirectory) Suggest(name string, n int) []ForceMatch {
+	key := forceKey(name)
+	if key == "" || n <= 0 {
+		return nil
+	}
+	alias := forceAliases[strings.Join(strings.Fields(strings.ToLower(name)), " ")]
+
+	var matches []ForceMatch
+	for _, f := range d.Forces() {
+		score := forceScore(key, f)
+		if f.ID == alias {
+			score = 1
+		}
+		if score >= 0.5 {
+			matches = append(matches, ForceMatch{Force: f, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// forceKey reduces a name to lower case words, without punctuation or words
+// common to the names of every force.
+func forceKey(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var kept []string
+	for _, w := range words {
+		if !forceStopWords[w] {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// forceScore scores how well the key of a name matches a force.
+func forceScore(key string, f Force) float64 {
+	var best float64
+	for _, candidate := range []string{forceKey(f.ID), forceKey(f.Name)} {
+		if candidate == "" {
+			continue
+		}
+		if key == candidate {
+			return 1
+		}
+		if key == initials(f.Name) {
+			best = maxFloat(best, 0.95)
+		}
+		if strings.HasPrefix(candidate, key) {
+			best = maxFloat(best, 0.8+0.15*float64(len(key))/float64(len(candidate)))
+		}
+		best = maxFloat(best, wordPrefixScore(key, candidate))
+		d := levenshtein(key, candidate)
+		best = maxFloat(best, 1-float64(d)/float64(maxInt(utf8.RuneCountInString(key), utf8.RuneCountInString(candidate))))
+	}
+	return best
+}
+
+// wordPrefixScore returns 0.85 if every word of key is a prefix of a word of
+// candidate in order, as in "west mids" for "west midlands", and 0 otherwise.
+func wordPrefixScore(key, candidate string) float64 {
+	words := strings.Fields(candidate)
+	i := 0
+	for _, k := range strings.Fields(key) {
+		for i < len(words) && !strings.HasPrefix(words[i], k) {
+			i++
+		}
+		if i == len(words) {
+			return 0
+		}
+		i++
+	}
+	return 0.85
+}
+
+// initials returns the first letter of each word of name, so "Greater
+// Manchester Police" becomes "gmp".
+func initials(name string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if w == "and" || w == "of" || w == "the" {
+			continue
+		}
+		b.WriteString(w[:1])
+	}
+	return b.String()
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Save writes the directory to the file at path.
+func (d *ForceDirectory) Save(path string) error {
+	b, err := json.Marshal(forceDirectoryJSON{Updated: d.Updated(), Forces: d.Forces()})
+	if err != nil {
+		return err
+	}
+	return writeFile(path, b)
+}
+
+// LoadForceDirectory reads a directory saved with Save.
+func LoadForceDirectory(path string) (*ForceDirectory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var saved forceDirectoryJSON
+	if err := json.NewDecoder(f).Decode(&saved); err != nil {
+		return nil, err
+	}
+	d := NewForceDirectory(saved.Forces)
+	d.updated = saved.Updated
+	return d, nil
+}
+
+// GetForceDirectory returns a directory of every force, including the
+// details returned by GetForceDetails.
+func (f *ForceService) GetForceDirectory(ctx context.Context) (*ForceDirectory, error) {
+	forces, _, err := f.GetForces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, force := range forces {
+		details, _, err := f.getForceDetails(ctx, force.ID)
+		if err != nil {
+			return nil, err
+		}
+		if details.ID == "" {
+			details.ID = force.ID
+		}
+		if details.Name == "" {
+			details.Name = force.Name
+		}
+		forces[i] = details
+	}
+	return NewForceDirectory(forces), nil
+}
+
+// CachedForceDirectory returns the directory saved at path if it is younger
+// than maxAge. Otherwise, or if the file is missing or cannot be decoded, the
+// directory is fetched with GetForceDirectory and saved to path.
+func (f *ForceService) CachedForceDirectory(ctx context.Context, path string, maxAge time.Duration) (*ForceDirectory, error) {
+	d, err := LoadForceDirectory(path)
+	if err == nil && time.Since(d.Updated()) < maxAge {
+		return d, nil
+	}
+	if err != nil && !cacheMiss(err) {
+		return nil, err
+	}
+
+	d, err = f.GetForceDirectory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Save(path); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
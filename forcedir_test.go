@@ -0,0 +1,168 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testForceDirectory() *ForceDirectory {
+	return NewForceDirectory([]Force{
+		{ID: "metropolitan", Name: "Metropolitan Police Service"},
+		{ID: "city-of-london", Name: "City of London Police"},
+		{ID: "west-midlands", Name: "West Midlands Police"},
+		{ID: "west-mercia", Name: "West Mercia Police"},
+		{ID: "west-yorkshire", Name: "West Yorkshire Police"},
+		{ID: "south-yorkshire", Name: "South Yorkshire Police"},
+		{ID: "greater-manchester", Name: "Greater Manchester Police"},
+		{ID: "leicestershire", Name: "Leicestershire Police"},
+		{ID: "btp", Name: "British Transport Police"},
+	})
+}
+
+func TestForceDirectory_Resolve(t *testing.T) {
+	d := testForceDirectory()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"metropolitan", "metropolitan"},
+		{"Met Police", "metropolitan"},
+		{"met", "metropolitan"},
+		{"West Mids", "west-midlands"},
+		{"west midlands police", "west-midlands"},
+		{"GMP", "greater-manchester"},
+		{"Greater Manchester", "greater-manchester"},
+		{"British Transport Police", "btp"},
+		{"Leicestershre", "leicestershire"},
+		{"City of London", "city-of-london"},
+		{"leics", "leicestershire"},
+	}
+	for _, tt := range tests {
+		got, err := d.Resolve(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("ForceDirectory.Resolve(%q) returned %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestForceDirectory_Resolve_ambiguous(t *testing.T) {
+	d := testForceDirectory()
+
+	_, err := d.Resolve("Yorkshire")
+	ferr, ok := err.(*ForceError)
+	if !ok || !ferr.Ambiguous {
+		t.Fatalf("ForceDirectory.Resolve returned %v, want an ambiguous *ForceError", err)
+	}
+	var ids []string
+	for _, s := range ferr.Suggestions {
+		ids = append(ids, s.Force.ID)
+	}
+	if want := []string{"south-yorkshire", "west-yorkshire"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ForceError.Suggestions were %v, want %v", ids, want)
+	}
+
+	_, err = d.Resolve("Narnia Constabulary")
+	if ferr, ok := err.(*ForceError); !ok || ferr.Ambiguous || len(ferr.Suggestions) != 0 {
+		t.Errorf("ForceDirectory.Resolve returned %v, want an unknown *ForceError", err)
+	}
+}
+
+func TestForceDirectory_Suggest(t *testing.T) {
+	d := testForceDirectory()
+	matches := d.Suggest("west", 3)
+	if len(matches) != 3 {
+		t.Fatalf("ForceDirectory.Suggest returned %v, want 3 matches", matches)
+	}
+	for _, m := range matches {
+		if m.Force.ID[:5] != "west-" {
+			t.Errorf("ForceDirectory.Suggest returned %v", m)
+		}
+	}
+}
+
+func TestForceDirectory_Validate(t *testing.T) {
+	d := testForceDirectory()
+	if err := d.Validate("west-midlands"); err != nil {
+		t.Errorf("ForceDirectory.Validate returned error: '%s'", err)
+	}
+	err := d.Validate("west-midland")
+	want := `unknown force "west-midland"; did you mean "west-midlands", "west-mercia"?`
+	if err == nil || err.Error() != want {
+		t.Errorf("ForceDirectory.Validate returned %v, want %s", err, want)
+	}
+	if err := (*ForceDirectory)(nil).Validate("anything"); err != nil {
+		t.Errorf("nil ForceDirectory.Validate returned error: '%s'", err)
+	}
+	if id, err := (*ForceDirectory)(nil).Resolve("anything"); err != nil || id != "anything" {
+		t.Errorf("nil ForceDirectory.Resolve returned %q, %v; want the name unchanged", id, err)
+	}
+}
+
+func TestClient_Forces(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Forces = testForceDirectory()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent for %s", r.URL)
+	})
+
+	if _, _, err := client.Neighborhood.GetNeighbourhoods(context.Background(), "west-mids"); err == nil {
+		t.Errorf("Neighborhood.GetNeighbourhoods returned no error for an unknown force")
+	}
+	if _, _, err := client.StopAndSearch.GetStopAndSearchesByForce(context.Background(), WithForce("met")); err == nil {
+		t.Errorf("StopAndSearch.GetStopAndSearchesByForce returned no error for an unknown force")
+	}
+}
+
+func TestForceService_CachedForceDirectory(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/forces", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `[{"id": "leicestershire", "name": "Leicestershire Police"}]`)
+	})
+	mux.HandleFunc("/forces/leicestershire", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "leicestershire", "name": "Leicestershire Police", "telephone": "101"}`)
+	})
+
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "forces.json")
+
+	for i := 0; i < 2; i++ {
+		d, err := client.Force.CachedForceDirectory(context.Background(), path, time.Hour)
+		if err != nil {
+			t.Fatalf("Force.CachedForceDirectory returned error: '%s'", err)
+		}
+		want := []Force{{ID: "leicestershire", Name: "Leicestershire Police", Telephone: "101"}}
+		if !reflect.DeepEqual(d.Forces(), want) {
+			t.Errorf("Force.CachedForceDirectory returned %v, want %v", d.Forces(), want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Force.CachedForceDirectory fetched the directory %d times, want 1", requests)
+	}
+
+	for _, corrupt := range []string{"", `{"updated": "2017-`, `{"forces": 1}`} {
+		if err := ioutil.WriteFile(path, []byte(corrupt), 0644); err != nil {
+			t.Fatal(err)
+		}
+		d, err := client.Force.CachedForceDirectory(context.Background(), path, time.Hour)
+		if err != nil || d.Len() != 1 {
+			t.Errorf("Force.CachedForceDirectory with cache %q returned %v, %v; want the directory rebuilt", corrupt, d, err)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package ukpolice
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// AllCrime is the category used by the data.police.uk API to refer to every
+// crime category. Aggregations use it as the key for totals.
+const AllCrime = "all-crime"
+
+// ErrInsufficientData is returned when a series is too short to fit a model.
+var ErrInsufficientData = errors.New("insufficient data: at least two full seasons are required")
+
+// SeriesPoint holds a single observation of a monthly series.
+type SeriesPoint struct {
+	Month string  `json:"month"`
+	Value float64 `json:"value"`
+}
+
+// Forecast holds a point forecast for a month along with the lower and upper
+// bounds of its prediction interval.
+type Forecast struct {
+	Month string  `json:"month"`
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+func (f Forecast) String() string {
+	return Stringify(f)
+}
+
+// BacktestResult holds the accuracy of forecasts made for held-out months.
+// MAPE ignores months where the actual value was zero.
+type BacktestResult struct {
+	Forecasts []Forecast    `json:"forecasts"`
+	Actual    []SeriesPoint `json:"actual"`
+	MAE       float64       `json:"mae"`
+	RMSE      float64       `json:"rmse"`
+	MAPE      float64       `json:"mape"`
+	// Coverage is the fraction of actual values that fell inside the
+	// prediction interval.
+	Coverage float64 `json:"coverage"`
+}
+
+func (b BacktestResult) String() string {
+	return Stringify(b)
+}
+
+// HoltWinters fits an additive Holt-Winters (triple exponential smoothing)
+// model to a monthly series.
+type HoltWinters struct {
+	// Alpha, Beta and Gamma are the level, trend and seasonal smoothing
+	// parameters and must lie between 0 and 1.
+	Alpha float64
+	Beta  float64
+	Gamma float64
+
+	// Period is the length of a season in months.
+	Period int
+
+	// Level is the coverage of prediction intervals, e.g. 0.95, and must lie
+	// strictly between 0 and 1.
+	Level float64
+}
+
+// NewHoltWinters returns a HoltWinters model with yearly seasonality, modest
+// smoothing parameters and 95% prediction intervals.
+func NewHoltWinters() *HoltWinters {
+	return &HoltWinters{
+		Alpha:  0.3,
+		Beta:   0.1,
+		Gamma:  0.2,
+		Period: 12,
+		Level:  0.95,
+	}
+}
+
+// Forecast fits the model to series and returns forecasts for the horizon
+// months that follow it. Series must be ordered by month, contain no gaps and
+// span at least two seasons.
+//
+// Prediction intervals assume normally distributed one-step errors and widen
+// with the horizon as described by Hyndman et al. for the additive model.
+// Counts cannot be negative so forecasts and bounds are floored at zero.
+func (hw *HoltWinters) Forecast(series []SeriesPoint, horizon int) ([]Forecast, error) {
+	m := hw.Period
+	if m < 1 {
+		return nil, errors.New("holt-winters period must be positive")
+	}
+	for _, p := range []float64{hw.Alpha, hw.Beta, hw.Gamma} {
+		if !(p >= 0 && p <= 1) {
+			return nil, errors.New("holt-winters smoothing parameters must lie between 0 and 1")
+		}
+	}
+	if !(hw.Level > 0 && hw.Level < 1) {
+		return nil, errors.New("holt-winters interval level must lie between 0 and 1")
+	}
+	if horizon < 0 {
+		return nil, errors.New("holt-winters horizon must not be negative")
+	}
+	if len(series) < 2*m {
+		return nil, ErrInsufficientData
+	}
+	if _, err := parseMonth(series[len(series)-1].Month); err != nil {
+		return nil, err
+	}
+
+	level, trend, season, sigma := hw.fit(series)
+	z := normalQuantile(0.5 + hw.Level/2)
+	n := len(series)
+	last := series[n-1].Month
+
+	forecasts := make([]Forecast, 0, horizon)
+	var variance float64
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			// the seasonal smoothing parameter of the equivalent ETS(A,A,A)
+			// model is Gamma*(1-Alpha), as Gamma here smooths against the
+			// updated level.
+			j := float64(h - 1)
+			c := hw.Alpha * (1 + j*hw.Beta)
+			if (h-1)%m == 0 {
+				c += hw.Gamma * (1 - hw.Alpha)
+			}
+			variance += c * c
+		}
+		value := level + float64(h)*trend + season[(n+h-1)%m]
+		width := z * sigma * math.Sqrt(1+variance)
+
+		forecasts = append(forecasts, Forecast{
+			Month: addMonths(last, h),
+			Value: math.Max(value, 0),
+			Lower: math.Max(value-width, 0),
+			Upper: math.Max(value+width, 0),
+		})
+	}
+
+	return forecasts, nil
+}
+
+// fit runs the smoothing equations over series and returns the final level,
+// trend and seasonal components along with the standard deviation of the
+// one-step-ahead errors.
+func (hw *HoltWinters) fit(series []SeriesPoint) (float64, float64, []float64, float64) {
+	m := hw.Period
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += series[i].Value
+		second += series[m+i].Value
+	}
+	first /= float64(m)
+	second /= float64(m)
+
+	// initial seasonal components are averaged over the first two seasons
+	// after removing the trend, and the level is that at the end of the first
+	// season.
+	trend := (second - first) / float64(m)
+	level := first + float64(m-1)/2*trend
+	season := make([]float64, m)
+	for i := 0; i < m; i++ {
+		offset := (float64(i) - float64(m-1)/2) * trend
+		season[i] = ((series[i].Value - first - offset) + (series[m+i].Value - second - offset)) / 2
+	}
+
+	var sse float64
+	var count int
+	for t := m; t < len(series); t++ {
+		y := series[t].Value
+		s := season[t%m]
+
+		resid := y - (level + trend + s)
+		sse += resid * resid
+		count++
+
+		newLevel := hw.Alpha*(y-s) + (1-hw.Alpha)*(level+trend)
+		trend = hw.Beta*(newLevel-level) + (1-hw.Beta)*trend
+		season[t%m] = hw.Gamma*(y-newLevel) + (1-hw.Gamma)*s
+		level = newLevel
+	}
+
+	return level, trend, season, math.Sqrt(sse / float64(count))
+}
+
+// Backtest holds out the last holdout months of series, forecasts them from
+// the remainder and reports how accurate the forecasts were.
+func (hw *HoltWinters) Backtest(series []SeriesPoint, holdout int) (*BacktestResult, error) {
+	if holdout < 1 || holdout >= len(series) {
+		return nil, errors.New("holdout must be between 1 and the length of the series")
+	}
+
+	train := series[:len(series)-holdout]
+	actual := series[len(series)-holdout:]
+
+	forecasts, err := hw.Forecast(train, holdout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BacktestResult{Forecasts: forecasts, Actual: actual}
+	var absErr, sqErr, pctErr float64
+	var pctCount, covered int
+	for i, f := range forecasts {
+		diff := actual[i].Value - f.Value
+		absErr += math.Abs(diff)
+		sqErr += diff * diff
+		if actual[i].Value != 0 {
+			pctErr += math.Abs(diff / actual[i].Value)
+			pctCount++
+		}
+		if actual[i].Value >= f.Lower && actual[i].Value <= f.Upper {
+			covered++
+		}
+	}
+
+	n := float64(holdout)
+	result.MAE = absErr / n
+	result.RMSE = math.Sqrt(sqErr / n)
+	if pctCount > 0 {
+		result.MAPE = 100 * pctErr / float64(pctCount)
+	}
+	result.Coverage = float64(covered) / n
+
+	return result, nil
+}
+
+// MonthlyCrimeCounts counts crimes by category and month. The returned series
+// are ordered by month and contain a zero for every month without a crime
+// between the earliest and latest month seen. Totals across all categories are
+// keyed by AllCrime.
+func MonthlyCrimeCounts(crimes []Crime) map[string][]SeriesPoint {
+	counts := make(map[string]map[string]int)
+	var first, last string
+	for _, c := range crimes {
+		if _, err := parseMonth(c.Month); err != nil {
+			continue
+		}
+		if first == "" || c.Month < first {
+			first = c.Month
+		}
+		if c.Month > last {
+			last = c.Month
+		}
+		for _, category := range []string{c.Category, AllCrime} {
+			if counts[category] == nil {
+				counts[category] = make(map[string]int)
+			}
+			counts[category][c.Month]++
+		}
+	}
+
+	series := make(map[string][]SeriesPoint)
+	if first == "" {
+		return series
+	}
+
+	months, _ := monthsBetween(first, last)
+	for category, byMonth := range counts {
+		points := make([]SeriesPoint, len(months))
+		for i, month := range months {
+			points[i] = SeriesPoint{Month: month, Value: float64(byMonth[month])}
+		}
+		series[category] = points
+	}
+	return series
+}
+
+// ForecastCrimes forecasts the monthly count of each crime category for the
+// horizon months after the latest month in crimes. Categories without enough
+// history to fit the model are omitted.
+func ForecastCrimes(crimes []Crime, hw *HoltWinters, horizon int) (map[string][]Forecast, error) {
+	forecasts := make(map[string][]Forecast)
+	for category, series := range MonthlyCrimeCounts(crimes) {
+		f, err := hw.Forecast(series, horizon)
+		if err == ErrInsufficientData {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		forecasts[category] = f
+	}
+	return forecasts, nil
+}
+
+// GetMonthlyCrimeCounts requests street level crimes for every month from
+// start to end inclusive and returns their monthly counts by category, as
+// produced by MonthlyCrimeCounts. Options should describe the area to query;
+// dates are set for each request.
+func (c *CrimeService) GetMonthlyCrimeCounts(ctx context.Context, start, end string, opts ...Option) (map[string][]SeriesPoint, error) {
+	months, err := monthsBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Crime
+	for _, month := range months {
+		crimes, _, err := c.GetStreetLevelCrimes(ctx, append(opts, WithDate(month))...)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, crimes...)
+	}
+
+	series := MonthlyCrimeCounts(all)
+	// fill months at either end of the range that had no crimes.
+	for category, points := range series {
+		byMonth := make(map[string]float64, len(points))
+		for _, p := range points {
+			byMonth[p.Month] = p.Value
+		}
+		filled := make([]SeriesPoint, len(months))
+		for i, month := range months {
+			filled[i] = SeriesPoint{Month: month, Value: byMonth[month]}
+		}
+		series[category] = filled
+	}
+	return series, nil
+}
+
+// normalQuantile returns the quantile function of the standard normal
+// distribution at p using Acklam's rational approximation.
+func normalQuantile(p float64) float64 {
+	if p <= 0 {
+		return math.Inf(-1)
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	a := []float64{-3.969683028665376e+01, 2.209460984245205e+02, -2.759285104469687e+02,
+		1.383577518672690e+02, -3.066479806614716e+01, 2.506628277459239e+00}
+	b := []float64{-5.447609879822406e+01, 1.615858368580409e+02, -1.556989798598866e+02,
+		6.680131188771972e+01, -1.328068155288572e+01}
+	c := []float64{-7.784894002430293e-03, -3.223964580411365e-01, -2.400758277161838e+00,
+		-2.549732539343734e+00, 4.374664141464968e+00, 2.938163982698783e+00}
+	d := []float64{7.784695709041462e-03, 3.224671290700398e-01, 2.445134137142996e+00,
+		3.754408661907416e+00}
+
+	const low = 0.02425
+	switch {
+	case p < low:
+		q := math.Sqrt(-2 * math.Log(p))
+		return (((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	case p > 1-low:
+		q := math.Sqrt(-2 * math.Log(1-p))
+		return -(((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	default:
+		q := p - 0.5
+		r := q * q
+		return (((((a[0]*r+a[1])*r+a[2])*r+a[3])*r+a[4])*r + a[5]) * q /
+			(((((b[0]*r+b[1])*r+b[2])*r+b[3])*r+b[4])*r + 1)
+	}
+}
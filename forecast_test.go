@@ -0,0 +1,146 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// seasonalSeries returns n months of a series with a linear trend and a
+// yearly cycle starting in January 2015.
+func seasonalSeries(n int) []SeriesPoint {
+	series := make([]SeriesPoint, n)
+	for i := range series {
+		series[i] = SeriesPoint{
+			Month: addMonths("2015-01", i),
+			Value: 100 + float64(i) + 20*math.Sin(2*math.Pi*float64(i)/12),
+		}
+	}
+	return series
+}
+
+func TestHoltWinters_Forecast(t *testing.T) {
+	series := seasonalSeries(48)
+	want := seasonalSeries(51)[48:]
+
+	forecasts, err := NewHoltWinters().Forecast(series, 3)
+	if err != nil {
+		t.Fatalf("HoltWinters.Forecast returned error: '%s'", err)
+	}
+	if len(forecasts) != 3 {
+		t.Fatalf("HoltWinters.Forecast returned %d forecasts, want 3", len(forecasts))
+	}
+
+	for i, f := range forecasts {
+		if f.Month != want[i].Month {
+			t.Errorf("forecast %d month is %v, want %v", i, f.Month, want[i].Month)
+		}
+		if math.Abs(f.Value-want[i].Value) > 5 {
+			t.Errorf("forecast %d value is %v, want approximately %v", i, f.Value, want[i].Value)
+		}
+		if f.Lower > f.Value || f.Upper < f.Value {
+			t.Errorf("forecast %d interval [%v, %v] does not contain %v", i, f.Lower, f.Upper, f.Value)
+		}
+	}
+	if w1, w3 := forecasts[0].Upper-forecasts[0].Lower, forecasts[2].Upper-forecasts[2].Lower; w3 < w1 {
+		t.Errorf("prediction interval should widen with the horizon; got %v then %v", w1, w3)
+	}
+}
+
+func TestHoltWinters_ForecastInsufficientData(t *testing.T) {
+	_, err := NewHoltWinters().Forecast(seasonalSeries(23), 1)
+	if err != ErrInsufficientData {
+		t.Errorf("HoltWinters.Forecast returned error %v, want %v", err, ErrInsufficientData)
+	}
+}
+
+func TestHoltWinters_ForecastInvalid(t *testing.T) {
+	series := seasonalSeries(48)
+	for _, tt := range []struct {
+		name    string
+		modify  func(*HoltWinters)
+		horizon int
+	}{
+		{"negative horizon", func(*HoltWinters) {}, -1},
+		{"alpha above 1", func(hw *HoltWinters) { hw.Alpha = 1.5 }, 1},
+		{"negative beta", func(hw *HoltWinters) { hw.Beta = -0.1 }, 1},
+		{"NaN gamma", func(hw *HoltWinters) { hw.Gamma = math.NaN() }, 1},
+		{"zero level", func(hw *HoltWinters) { hw.Level = 0 }, 1},
+	} {
+		hw := NewHoltWinters()
+		tt.modify(hw)
+		if _, err := hw.Forecast(series, tt.horizon); err == nil {
+			t.Errorf("HoltWinters.Forecast with %s should have returned an error", tt.name)
+		}
+	}
+	if f, err := NewHoltWinters().Forecast(series, 0); err != nil || len(f) != 0 {
+		t.Errorf("HoltWinters.Forecast with no horizon returned %v, %v", f, err)
+	}
+}
+
+func TestHoltWinters_Backtest(t *testing.T) {
+	result, err := NewHoltWinters().Backtest(seasonalSeries(48), 6)
+	if err != nil {
+		t.Fatalf("HoltWinters.Backtest returned error: '%s'", err)
+	}
+	if len(result.Forecasts) != 6 || len(result.Actual) != 6 {
+		t.Fatalf("HoltWinters.Backtest returned %d forecasts and %d actual values, want 6",
+			len(result.Forecasts), len(result.Actual))
+	}
+	if result.MAPE > 5 {
+		t.Errorf("HoltWinters.Backtest MAPE is %v, want less than 5", result.MAPE)
+	}
+	if result.Coverage < 0.5 {
+		t.Errorf("HoltWinters.Backtest coverage is %v, want at least 0.5", result.Coverage)
+	}
+}
+
+func TestMonthlyCrimeCounts(t *testing.T) {
+	crimes := []Crime{
+		{Category: "burglary", Month: "2017-01"},
+		{Category: "burglary", Month: "2017-03"},
+		{Category: "drugs", Month: "2017-03"},
+		{Category: "drugs", Month: ""},
+	}
+
+	got := MonthlyCrimeCounts(crimes)
+	want := map[string][]SeriesPoint{
+		"burglary": {{"2017-01", 1}, {"2017-02", 0}, {"2017-03", 1}},
+		"drugs":    {{"2017-01", 0}, {"2017-02", 0}, {"2017-03", 1}},
+		AllCrime:   {{"2017-01", 1}, {"2017-02", 0}, {"2017-03", 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MonthlyCrimeCounts returned %v, want %v", got, want)
+	}
+}
+
+func TestCrimeService_GetMonthlyCrimeCounts(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/crimes-street/all-crime", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("date") == "2017-01" {
+			fmt.Fprint(w, rawCrime)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	series, err := client.Crime.GetMonthlyCrimeCounts(context.Background(), "2016-12", "2017-02",
+		WithLatLong("52.629729", "-1.131592"))
+	if err != nil {
+		t.Errorf("Crime.GetMonthlyCrimeCounts returned error: '%s'", err)
+	}
+
+	want := map[string][]SeriesPoint{
+		"anti-social-behaviour": {{"2016-12", 0}, {"2017-01", 1}, {"2017-02", 0}},
+		AllCrime:                {{"2016-12", 0}, {"2017-01", 1}, {"2017-02", 0}},
+	}
+	if !reflect.DeepEqual(series, want) {
+		t.Errorf("Crime.GetMonthlyCrimeCounts returned %v, want %v", series, want)
+	}
+}
@@ -0,0 +1,135 @@
+package ukpolice
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// earthRadius is the mean radius of the Earth in metres.
+const earthRadius = 6371008.8
+
+// Point holds a position in decimal degrees.
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (p Point) String() string {
+	return Stringify(p)
+}
+
+// Point parses the latitude and longitude of a location. An error is returned
+// if either is missing or invalid, as is the case for crimes and searches with
+// no location.
+func (l Location) Point() (Point, error) {
+	if l.Latitude == "" || l.Longitude == "" {
+		return Point{}, errors.New("location has no coordinates")
+	}
+	lat, err := strconv.ParseFloat(l.Latitude, 64)
+	if err != nil {
+		return Point{}, err
+	}
+	lng, err := strconv.ParseFloat(l.Longitude, 64)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{Latitude: lat, Longitude: lng}, nil
+}
+
+// distance returns the great-circle distance between a and b in metres.
+func distance(a, b Point) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// planar projects points onto a flat plane in metres around a reference
+// latitude. It is accurate enough for distances within a city or force.
+type planar struct {
+	cosLat float64
+}
+
+func newPlanar(latitude float64) planar {
+	return planar{cosLat: math.Cos(latitude * math.Pi / 180)}
+}
+
+func (pl planar) project(p Point) (float64, float64) {
+	x := earthRadius * p.Longitude * math.Pi / 180 * pl.cosLat
+	y := earthRadius * p.Latitude * math.Pi / 180
+	return x, y
+}
+
+func (pl planar) unproject(x, y float64) Point {
+	return Point{
+		Latitude:  y / earthRadius * 180 / math.Pi,
+		Longitude: x / (earthRadius * pl.cosLat) * 180 / math.Pi,
+	}
+}
+
+// convexHull returns the convex hull of points in counter-clockwise order
+// using Andrew's monotone chain algorithm. Longitude and latitude are treated
+// as planar coordinates. Fewer than three distinct points are returned as is.
+func convexHull(points []Point) []Point {
+	pts := make([]Point, len(points))
+	copy(pts, points)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].Longitude != pts[j].Longitude {
+			return pts[i].Longitude < pts[j].Longitude
+		}
+		return pts[i].Latitude < pts[j].Latitude
+	})
+
+	// remove duplicates
+	unique := pts[:0]
+	for i, p := range pts {
+		if i == 0 || p != pts[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	cross := func(o, a, b Point) float64 {
+		return (a.Longitude-o.Longitude)*(b.Latitude-o.Latitude) -
+			(a.Latitude-o.Latitude)*(b.Longitude-o.Longitude)
+	}
+
+	hull := make([]Point, 0, 2*len(unique))
+	for _, p := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
+
+// pointInPolygon reports whether p lies inside the ring of points, which may
+// be open or closed, using the even-odd rule on longitude and latitude.
+func pointInPolygon(p Point, ring []Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,77 @@
+package ukpolice
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLocation_Point(t *testing.T) {
+	p, err := Location{Latitude: "52.634407", Longitude: "-1.133653"}.Point()
+	if err != nil {
+		t.Fatalf("Location.Point returned error: '%s'", err)
+	}
+	want := Point{Latitude: 52.634407, Longitude: -1.133653}
+	if p != want {
+		t.Errorf("Location.Point returned %v, want %v", p, want)
+	}
+
+	if _, err := (Location{}).Point(); err == nil {
+		t.Errorf("Location.Point should have returned an error for a missing location")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Leicester to Nottingham is roughly 36km.
+	d := distance(Point{52.6369, -1.1398}, Point{52.9548, -1.1581})
+	if math.Abs(d-35400) > 500 {
+		t.Errorf("distance returned %v, want approximately 35400", d)
+	}
+}
+
+func TestPlanar(t *testing.T) {
+	pl := newPlanar(52.6)
+	p := Point{Latitude: 52.634407, Longitude: -1.133653}
+	got := pl.unproject(pl.project(p))
+	if math.Abs(got.Latitude-p.Latitude) > 1e-9 || math.Abs(got.Longitude-p.Longitude) > 1e-9 {
+		t.Errorf("unproject(project(%v)) returned %v", p, got)
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []Point{
+		{0, 0}, {0, 2}, {2, 2}, {2, 0}, {1, 1}, {0, 0}, {1, 0},
+	}
+	want := []Point{{0, 0}, {0, 2}, {2, 2}, {2, 0}}
+	if got := convexHull(points); !reflect.DeepEqual(got, want) {
+		t.Errorf("convexHull returned %v, want %v", got, want)
+	}
+
+	two := []Point{{1, 1}, {0, 0}, {1, 1}}
+	if got := convexHull(two); !reflect.DeepEqual(got, []Point{{0, 0}, {1, 1}}) {
+		t.Errorf("convexHull returned %v, want %v", got, []Point{{0, 0}, {1, 1}})
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	// a square with a notch cut from its top edge.
+	ring := []Point{
+		{52.60, -1.20}, {52.60, -1.10}, {52.70, -1.10}, {52.70, -1.14},
+		{52.65, -1.15}, {52.70, -1.16}, {52.70, -1.20},
+	}
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{52.62, -1.15}, true},
+		{Point{52.69, -1.15}, false},
+		{Point{52.69, -1.18}, true},
+		{Point{52.75, -1.15}, false},
+		{Point{52.65, -1.25}, false},
+	}
+	for _, tt := range tests {
+		if got := pointInPolygon(tt.p, ring); got != tt.want {
+			t.Errorf("pointInPolygon(%v) returned %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ukpolice
+
+// FeatureCollection is a GeoJSON (RFC 7946) feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Coordinates hold the nested arrays of
+// longitude, latitude pairs appropriate to Type.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// NewFeatureCollection returns a FeatureCollection holding features.
+func NewFeatureCollection(features ...Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return &FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// pointGeometry returns a GeoJSON Point.
+func pointGeometry(p Point) Geometry {
+	return Geometry{Type: "Point", Coordinates: position(p)}
+}
+
+// polygonGeometry returns a GeoJSON Polygon from rings, the first of which is
+// the exterior. Rings are closed if necessary.
+func polygonGeometry(rings ...[]Point) Geometry {
+	return Geometry{Type: "Polygon", Coordinates: polygonCoordinates(rings)}
+}
+
+// multiPolygonGeometry returns a GeoJSON MultiPolygon from polygons, each
+// holding an exterior ring followed by any holes.
+func multiPolygonGeometry(polygons [][][]Point) Geometry {
+	coords := make([][][][2]float64, 0, len(polygons))
+	for _, rings := range polygons {
+		coords = append(coords, polygonCoordinates(rings))
+	}
+	return Geometry{Type: "MultiPolygon", Coordinates: coords}
+}
+
+func polygonCoordinates(rings [][]Point) [][][2]float64 {
+	coords := make([][][2]float64, 0, len(rings))
+	for _, ring := range rings {
+		if len(ring) == 0 {
+			continue
+		}
+		r := make([][2]float64, 0, len(ring)+1)
+		for _, p := range ring {
+			r = append(r, position(p))
+		}
+		if ring[0] != ring[len(ring)-1] {
+			r = append(r, position(ring[0]))
+		}
+		coords = append(coords, r)
+	}
+	return coords
+}
+
+func position(p Point) [2]float64 {
+	return [2]float64{p.Longitude, p.Latitude}
+}
@@ -0,0 +1,207 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GridShape is the shape of the cells in a Grid.
+type GridShape int
+
+// Supported grid cell shapes.
+const (
+	SquareGrid GridShape = iota
+	HexagonGrid
+)
+
+// standardParallel is the latitude of true scale of the equal-area projection
+// used for grids. It is chosen to minimise shape distortion across Great
+// Britain.
+const standardParallel = 54.0
+
+// Grid bins points into equal-area square or hexagonal cells. Cells are laid
+// out on a Lambert cylindrical equal-area projection with a fixed origin, so a
+// cell ID always refers to the same area regardless of the data being binned.
+type Grid struct {
+	Shape GridShape
+	// Size is the length of a cell edge in metres. A size that is not
+	// positive is taken as 250m.
+	Size float64
+}
+
+// defaultGridSize is the cell size used for a Grid without a positive Size.
+const defaultGridSize = 250
+
+// withDefaults returns g with the default size if its size is not positive.
+func (g Grid) withDefaults() Grid {
+	if !(g.Size > 0) {
+		g.Size = defaultGridSize
+	}
+	return g
+}
+
+// GridCell holds the counts for a single cell of a Grid.
+type GridCell struct {
+	ID string `json:"id"`
+	// Counts holds the number of items in the cell by crime category or
+	// search outcome.
+	Counts  map[string]int `json:"counts"`
+	Total   int            `json:"total"`
+	Centre  Point          `json:"centre"`
+	Polygon []Point        `json:"polygon"`
+}
+
+func (c GridCell) String() string {
+	return Stringify(c)
+}
+
+// equalArea projects p onto the plane used for grids in metres.
+func equalArea(p Point) (float64, float64) {
+	cos0 := math.Cos(standardParallel * math.Pi / 180)
+	x := earthRadius * p.Longitude * math.Pi / 180 * cos0
+	y := earthRadius * math.Sin(p.Latitude*math.Pi/180) / cos0
+	return x, y
+}
+
+// inverseEqualArea is the inverse of equalArea.
+func inverseEqualArea(x, y float64) Point {
+	cos0 := math.Cos(standardParallel * math.Pi / 180)
+	return Point{
+		Latitude:  math.Asin(y*cos0/earthRadius) * 180 / math.Pi,
+		Longitude: x / (earthRadius * cos0) * 180 / math.Pi,
+	}
+}
+
+// cell returns the integer coordinates of the cell containing p. Hexagons use
+// axial coordinates.
+func (g Grid) cell(p Point) (int, int) {
+	x, y := equalArea(p)
+	if g.Shape == SquareGrid {
+		return int(math.Floor(x / g.Size)), int(math.Floor(y / g.Size))
+	}
+
+	// pointy-topped hexagons, rounded via cube coordinates.
+	fq := (math.Sqrt(3)/3*x - y/3) / g.Size
+	fr := (2.0 / 3 * y) / g.Size
+	fs := -fq - fr
+	q, r, s := math.Round(fq), math.Round(fr), math.Round(fs)
+	dq, dr, ds := math.Abs(q-fq), math.Abs(r-fr), math.Abs(s-fs)
+	if dq > dr && dq > ds {
+		q = -r - s
+	} else if dr > ds {
+		r = -q - s
+	}
+	return int(q), int(r)
+}
+
+// cellID returns the stable ID of the cell at i, j.
+func (g Grid) cellID(i, j int) string {
+	prefix := "sq"
+	if g.Shape == HexagonGrid {
+		prefix = "hex"
+	}
+	return fmt.Sprintf("%s%g_%d_%d", prefix, g.Size, i, j)
+}
+
+// CellID returns the ID of the cell containing p.
+func (g Grid) CellID(p Point) string {
+	g = g.withDefaults()
+	return g.cellID(g.cell(p))
+}
+
+// geometry returns the centre and polygon of the cell at i, j.
+func (g Grid) geometry(i, j int) (Point, []Point) {
+	if g.Shape == SquareGrid {
+		x, y := float64(i)*g.Size, float64(j)*g.Size
+		return inverseEqualArea(x+g.Size/2, y+g.Size/2), []Point{
+			inverseEqualArea(x, y),
+			inverseEqualArea(x+g.Size, y),
+			inverseEqualArea(x+g.Size, y+g.Size),
+			inverseEqualArea(x, y+g.Size),
+		}
+	}
+
+	cx := g.Size * (math.Sqrt(3)*float64(i) + math.Sqrt(3)/2*float64(j))
+	cy := g.Size * 1.5 * float64(j)
+	polygon := make([]Point, 6)
+	for k := range polygon {
+		angle := math.Pi / 180 * float64(60*k-30)
+		polygon[k] = inverseEqualArea(cx+g.Size*math.Cos(angle), cy+g.Size*math.Sin(angle))
+	}
+	return inverseEqualArea(cx, cy), polygon
+}
+
+// bin counts each point under its key and returns the occupied cells ordered
+// by ID.
+func (g Grid) bin(n int, item func(i int) (Point, string, bool)) []GridCell {
+	g = g.withDefaults()
+	type key struct{ i, j int }
+	cells := make(map[key]*GridCell)
+	for k := 0; k < n; k++ {
+		p, label, ok := item(k)
+		if !ok {
+			continue
+		}
+		i, j := g.cell(p)
+		c, ok := cells[key{i, j}]
+		if !ok {
+			c = &GridCell{ID: g.cellID(i, j), Counts: make(map[string]int)}
+			c.Centre, c.Polygon = g.geometry(i, j)
+			cells[key{i, j}] = c
+		}
+		c.Counts[label]++
+		c.Total++
+	}
+
+	result := make([]GridCell, 0, len(cells))
+	for _, c := range cells {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// BinCrimes counts crimes by category in each cell. Crimes with no location
+// are ignored.
+func (g Grid) BinCrimes(crimes []Crime) []GridCell {
+	return g.bin(len(crimes), func(i int) (Point, string, bool) {
+		p, err := crimes[i].Location.Point()
+		return p, crimes[i].Category, err == nil
+	})
+}
+
+// BinSearches counts stop and searches by outcome ID in each cell, or by the
+// outcome description for searches without an ID. Searches with no location
+// are ignored.
+func (g Grid) BinSearches(searches []Search) []GridCell {
+	return g.bin(len(searches), func(i int) (Point, string, bool) {
+		p, err := searches[i].Location.Point()
+		outcome := searches[i].Outcome.ID
+		if outcome == "" {
+			outcome = searches[i].Outcome.Desc
+		}
+		if outcome == "" {
+			outcome = "none"
+		}
+		return p, outcome, err == nil
+	})
+}
+
+// GridGeoJSON returns a GeoJSON feature collection holding a polygon for each
+// cell. The cell ID is used as the feature ID, and the properties hold the
+// total as "total" and the counts by label as "counts".
+func GridGeoJSON(cells []GridCell) ([]byte, error) {
+	features := make([]Feature, 0, len(cells))
+	for _, c := range cells {
+		properties := map[string]interface{}{"total": c.Total, "counts": c.Counts}
+		features = append(features, Feature{
+			Type:       "Feature",
+			ID:         c.ID,
+			Geometry:   polygonGeometry(c.Polygon),
+			Properties: properties,
+		})
+	}
+	return json.Marshal(NewFeatureCollection(features...))
+}
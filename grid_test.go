@@ -0,0 +1,125 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGrid_BinCrimes(t *testing.T) {
+	crimes := []Crime{
+		crimeAt("burglary", 52.63400, -1.13300),
+		crimeAt("burglary", 52.63401, -1.13301),
+		crimeAt("drugs", 52.63402, -1.13300),
+		crimeAt("drugs", 52.70000, -1.13300),
+		{Category: "other-crime"},
+	}
+
+	for _, g := range []Grid{{SquareGrid, 250}, {HexagonGrid, 250}} {
+		cells := g.BinCrimes(crimes)
+		if len(cells) != 2 {
+			t.Fatalf("Grid.BinCrimes returned %d cells, want 2", len(cells))
+		}
+
+		var total int
+		for _, c := range cells {
+			total += c.Total
+			if c.ID != g.CellID(c.Centre) {
+				t.Errorf("cell %v has centre in cell %v", c.ID, g.CellID(c.Centre))
+			}
+		}
+		if total != 4 {
+			t.Errorf("Grid.BinCrimes binned %d crimes, want 4", total)
+		}
+
+		busy := cells[0]
+		if busy.Total != 3 {
+			busy = cells[1]
+		}
+		want := map[string]int{"burglary": 2, "drugs": 1}
+		if !reflect.DeepEqual(busy.Counts, want) {
+			t.Errorf("Grid.BinCrimes counts are %v, want %v", busy.Counts, want)
+		}
+		if busy.ID != g.CellID(Point{52.63400, -1.13300}) {
+			t.Errorf("Grid.BinCrimes cell ID %v is not stable", busy.ID)
+		}
+	}
+}
+
+func TestGrid_Geometry(t *testing.T) {
+	square := Grid{SquareGrid, 500}
+	_, polygon := square.geometry(-3, 17220)
+	var area float64
+	for i := range polygon {
+		x1, y1 := equalArea(polygon[i])
+		x2, y2 := equalArea(polygon[(i+1)%len(polygon)])
+		area += x1*y2 - x2*y1
+	}
+	if math.Abs(math.Abs(area/2)-500*500) > 1 {
+		t.Errorf("square cell area is %v, want %v", math.Abs(area/2), 500*500)
+	}
+
+	hex := Grid{HexagonGrid, 200}
+	centre, polygon := hex.geometry(-50, 28700)
+	if len(polygon) != 6 {
+		t.Fatalf("hexagon has %d vertices, want 6", len(polygon))
+	}
+	for _, p := range polygon {
+		if d := distance(centre, p); math.Abs(d-200) > 20 {
+			t.Errorf("hexagon vertex is %vm from its centre, want approximately 200m", d)
+		}
+	}
+}
+
+func TestGrid_BinSearches(t *testing.T) {
+	searches := []Search{
+		{Location: Location{Latitude: "52.634407", Longitude: "-1.133653"}, Outcome: SearchOutcome{ID: "bu-arrest", Desc: "Arrest", SearchHappened: true}},
+		{Location: Location{Latitude: "52.634407", Longitude: "-1.133653"}, Outcome: SearchOutcome{ID: "bu-arrest", Desc: "Suspect arrested", SearchHappened: true}},
+		{Location: Location{Latitude: "52.634407", Longitude: "-1.133653"}, Outcome: SearchOutcome{Desc: "Local resolution", SearchHappened: true}},
+		{Location: Location{Latitude: "52.634407", Longitude: "-1.133653"}},
+		{},
+	}
+	cells := Grid{HexagonGrid, 100}.BinSearches(searches)
+	want := map[string]int{"bu-arrest": 2, "Local resolution": 1, "none": 1}
+	if len(cells) != 1 || !reflect.DeepEqual(cells[0].Counts, want) {
+		t.Errorf("Grid.BinSearches returned %v, want one cell with counts %v", cells, want)
+	}
+}
+
+func TestGrid_DefaultSize(t *testing.T) {
+	crimes := []Crime{crimeAt("burglary", 52.634, -1.133)}
+	want := Grid{HexagonGrid, 250}.BinCrimes(crimes)
+	for _, size := range []float64{0, -100, math.NaN()} {
+		g := Grid{HexagonGrid, size}
+		if got := g.BinCrimes(crimes); !reflect.DeepEqual(got, want) {
+			t.Errorf("Grid%v.BinCrimes returned %v, want %v", g, got, want)
+		}
+		if got := g.CellID(Point{Latitude: 52.634, Longitude: -1.133}); got != want[0].ID {
+			t.Errorf("Grid%v.CellID returned %q, want %q", g, got, want[0].ID)
+		}
+	}
+}
+
+func TestGridGeoJSON(t *testing.T) {
+	cells := Grid{SquareGrid, 250}.BinCrimes([]Crime{crimeAt("burglary", 52.634, -1.133), crimeAt("total", 52.634, -1.133)})
+	b, err := GridGeoJSON(cells)
+	if err != nil {
+		t.Fatalf("GridGeoJSON returned error: '%s'", err)
+	}
+
+	var fc FeatureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+	f := fc.Features[0]
+	counts, _ := f.Properties["counts"].(map[string]interface{})
+	if f.ID != cells[0].ID || f.Properties["total"] != float64(2) || counts["burglary"] != float64(1) ||
+		counts["total"] != float64(1) || f.Geometry.Type != "Polygon" {
+		t.Errorf("GridGeoJSON returned %v", f)
+	}
+	ring := f.Geometry.Coordinates.([]interface{})[0].([]interface{})
+	if len(ring) != 5 {
+		t.Errorf("GridGeoJSON returned a ring of %d positions, want a closed ring of 5", len(ring))
+	}
+}
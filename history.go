@@ -0,0 +1,70 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetForceHistory returns every stop and search reported by a police force.
+// The availability index is used to request only the months in which the
+// force has stop and search data. Searches with no location are merged in and
+// Force is set on every search.
+//
+// The API does not give searches a stable ID, so a search returned by both
+// endpoints is recognised by having identical fields. Identical searches
+// returned by one endpoint, such as a group searched together, are kept.
+func (s *StopAndSearchService) GetForceHistory(ctx context.Context, force string) ([]Search, error) {
+	idx, err := s.api.Availability.GetAvailabilityIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []Search
+	for _, month := range idx.MonthsFor(force) {
+		located, _, err := s.GetStopAndSearchesByForce(ctx, WithForce(force), WithDate(month))
+		if err != nil {
+			return nil, err
+		}
+		unlocated, _, err := s.GetStopAndSearchesWithNoLocation(ctx, WithForce(force), WithDate(month))
+		if err != nil {
+			return nil, err
+		}
+
+		searches, err := mergeSearches(force, located, unlocated)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, searches...)
+	}
+	return history, nil
+}
+
+// mergeSearches sets Force on every search and returns the searches in a
+// followed by those in b that a does not already hold.
+func mergeSearches(force string, a, b []Search) ([]Search, error) {
+	seen := make(map[string]int)
+	merged := make([]Search, 0, len(a)+len(b))
+	for _, s := range a {
+		s.Force = force
+		key, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		seen[string(key)]++
+		merged = append(merged, s)
+	}
+
+	for _, s := range b {
+		s.Force = force
+		key, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[string(key)] > 0 {
+			seen[string(key)]--
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged, nil
+}
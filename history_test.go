@@ -0,0 +1,68 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestStopAndSearchService_GetForceHistory(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/crime-last-updated", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"date": "2017-03-01"}`)
+	})
+	mux.HandleFunc("/crimes-street-dates", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"date": "2017-02", "stop-and-search": ["leicestershire"]},
+			{"date": "2017-01", "stop-and-search": ["cleveland"]},
+			{"date": "2016-12", "stop-and-search": ["cleveland", "leicestershire"]}
+		]`)
+	})
+
+	var requested []string
+	unlocated := `{"age_range": "over 34", "datetime": "2017-01-24T01:50:00+00:00", "location": null, "outcome": false}`
+	mux.HandleFunc("/stops-force", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if f := r.URL.Query().Get("force"); f != "leicestershire" {
+			t.Errorf("requested force %q, want leicestershire", f)
+		}
+		requested = append(requested, r.URL.Query().Get("date"))
+		// the same search twice, e.g. two people searched together.
+		fmt.Fprintf(w, `[%s, %s]`, unlocated, unlocated)
+	})
+	mux.HandleFunc("/stops-no-location", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("date") == "2017-02" {
+			fmt.Fprintf(w, `[%s, {"age_range": "18-24", "outcome": false}]`, unlocated)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	searches, err := client.StopAndSearch.GetForceHistory(context.Background(), "leicestershire")
+	if err != nil {
+		t.Fatalf("StopAndSearch.GetForceHistory returned error: '%s'", err)
+	}
+
+	if want := []string{"2016-12", "2017-02"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("StopAndSearch.GetForceHistory requested months %v, want %v", requested, want)
+	}
+
+	// two identical searches per month plus the one only known to the no
+	// location endpoint.
+	if len(searches) != 5 {
+		t.Fatalf("StopAndSearch.GetForceHistory returned %d searches, want 5", len(searches))
+	}
+	for _, s := range searches {
+		if s.Force != "leicestershire" {
+			t.Errorf("StopAndSearch.GetForceHistory returned a search with force %q", s.Force)
+		}
+	}
+	if searches[4].AgeRange != Age18To24 {
+		t.Errorf("StopAndSearch.GetForceHistory did not merge the search with no location: %v", searches[4])
+	}
+}
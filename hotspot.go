@@ -0,0 +1,388 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Hotspot holds a cluster of crimes.
+type Hotspot struct {
+	Centroid Point `json:"centroid"`
+	Count    int   `json:"count"`
+	// Category is the most common category within the hotspot.
+	Category   string         `json:"category"`
+	Categories map[string]int `json:"categories"`
+	// Hull is the convex hull of the crime locations in the hotspot. It has
+	// fewer than three points when every crime shares one or two snap points.
+	Hull []Point `json:"hull"`
+	// Members holds the index of each crime in the hotspot within the slice
+	// the hotspots were detected from.
+	Members []int `json:"members"`
+}
+
+func (h Hotspot) String() string {
+	return Stringify(h)
+}
+
+// DBSCAN detects hotspots using density-based spatial clustering.
+//
+// The data.police.uk API anonymises crimes by snapping them to the centre of
+// the nearest street or public place, so many crimes share identical
+// coordinates and neighbouring snap points are commonly 50-200 metres apart.
+// Radius should therefore be large enough to join adjacent snap points, and
+// MinCrimes counts crimes rather than distinct locations.
+type DBSCAN struct {
+	// Radius is the neighbourhood radius in metres. The default of 250m is
+	// used if it is not positive.
+	Radius float64
+	// MinCrimes is the number of crimes within Radius of a snap point,
+	// including those at the point itself, for it to seed a hotspot. The
+	// default of 20 is used if it is not positive.
+	MinCrimes int
+}
+
+// NewDBSCAN returns a DBSCAN with parameters suited to city-scale street
+// level crime data.
+func NewDBSCAN() *DBSCAN {
+	return &DBSCAN{Radius: 250, MinCrimes: 20}
+}
+
+// snapPoint holds the crimes sharing one set of coordinates.
+type snapPoint struct {
+	point   Point
+	x, y    float64
+	members []int
+}
+
+// groupSnapPoints groups crimes with identical coordinates and projects them
+// onto a plane. Crimes with no location are ignored.
+func groupSnapPoints(crimes []Crime) []*snapPoint {
+	byPoint := make(map[Point]*snapPoint)
+	var points []*snapPoint
+	for i, c := range crimes {
+		p, err := c.Location.Point()
+		if err != nil {
+			continue
+		}
+		sp, ok := byPoint[p]
+		if !ok {
+			sp = &snapPoint{point: p}
+			byPoint[p] = sp
+			points = append(points, sp)
+		}
+		sp.members = append(sp.members, i)
+	}
+
+	if len(points) == 0 {
+		return nil
+	}
+	pl := newPlanar(points[0].point.Latitude)
+	for _, sp := range points {
+		sp.x, sp.y = pl.project(sp.point)
+	}
+	return points
+}
+
+// Hotspots returns the hotspots within crimes ordered by descending count.
+// Crimes that do not belong to a hotspot are treated as noise.
+func (d *DBSCAN) Hotspots(crimes []Crime) []Hotspot {
+	points := groupSnapPoints(crimes)
+	radius, minCrimes := d.Radius, d.MinCrimes
+	if !(radius > 0) {
+		radius = NewDBSCAN().Radius
+	}
+	if minCrimes <= 0 {
+		minCrimes = NewDBSCAN().MinCrimes
+	}
+
+	// index points by grid cells the size of the radius so that neighbours
+	// are found in the surrounding nine cells.
+	type cell struct{ x, y int }
+	cellOf := func(sp *snapPoint) cell {
+		return cell{int(math.Floor(sp.x / radius)), int(math.Floor(sp.y / radius))}
+	}
+	grid := make(map[cell][]int)
+	for i, sp := range points {
+		c := cellOf(sp)
+		grid[c] = append(grid[c], i)
+	}
+	neighbours := func(i int) []int {
+		c := cellOf(points[i])
+		var n []int
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for _, j := range grid[cell{c.x + dx, c.y + dy}] {
+					if math.Hypot(points[i].x-points[j].x, points[i].y-points[j].y) <= radius {
+						n = append(n, j)
+					}
+				}
+			}
+		}
+		return n
+	}
+	weight := func(idx []int) int {
+		var w int
+		for _, j := range idx {
+			w += len(points[j].members)
+		}
+		return w
+	}
+
+	const unvisited, noise = 0, -1
+	labels := make([]int, len(points))
+	var clusters [][]int
+	for i := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		n := neighbours(i)
+		if weight(n) < minCrimes {
+			labels[i] = noise
+			continue
+		}
+
+		clusters = append(clusters, nil)
+		label := len(clusters)
+		labels[i] = label
+		queue := n
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if labels[j] == noise {
+				labels[j] = label
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = label
+			if jn := neighbours(j); weight(jn) >= minCrimes {
+				queue = append(queue, jn...)
+			}
+		}
+	}
+
+	for i, l := range labels {
+		if l > 0 {
+			clusters[l-1] = append(clusters[l-1], i)
+		}
+	}
+
+	hotspots := make([]Hotspot, 0, len(clusters))
+	for _, cluster := range clusters {
+		sps := make([]*snapPoint, len(cluster))
+		for i, j := range cluster {
+			sps[i] = points[j]
+		}
+		hotspots = append(hotspots, newHotspot(crimes, sps))
+	}
+	sortHotspots(hotspots)
+	return hotspots
+}
+
+// KernelDensity detects hotspots as connected areas of high kernel density
+// estimated over a square grid.
+type KernelDensity struct {
+	// CellSize is the width of a grid cell in metres. The default of 50m is
+	// used if it is not positive.
+	CellSize float64
+	// Bandwidth is the radius of the quartic kernel in metres. It should be
+	// at least the typical spacing between snap points. The default of 300m
+	// is used if it is not positive.
+	Bandwidth float64
+	// Threshold is the fraction of the peak density, between 0 and 1, that a
+	// cell must reach to form part of a hotspot.
+	Threshold float64
+}
+
+// NewKernelDensity returns a KernelDensity with parameters suited to
+// city-scale street level crime data.
+func NewKernelDensity() *KernelDensity {
+	return &KernelDensity{CellSize: 50, Bandwidth: 300, Threshold: 0.5}
+}
+
+// Hotspots returns the hotspots within crimes ordered by descending count.
+// Each hotspot holds the crimes located in a connected group of cells whose
+// density reaches the threshold.
+func (k *KernelDensity) Hotspots(crimes []Crime) []Hotspot {
+	points := groupSnapPoints(crimes)
+	if len(points) == 0 {
+		return []Hotspot{}
+	}
+
+	size, bandwidth := k.CellSize, k.Bandwidth
+	if !(size > 0) {
+		size = NewKernelDensity().CellSize
+	}
+	if !(bandwidth > 0) {
+		bandwidth = NewKernelDensity().Bandwidth
+	}
+
+	type cell struct{ x, y int }
+	cellOf := func(x, y float64) cell {
+		return cell{int(math.Floor(x / size)), int(math.Floor(y / size))}
+	}
+
+	// accumulate the density of every cell within the bandwidth of a point.
+	density := make(map[cell]float64)
+	reach := int(math.Ceil(bandwidth / size))
+	for _, sp := range points {
+		c := cellOf(sp.x, sp.y)
+		w := float64(len(sp.members))
+		for dx := -reach; dx <= reach; dx++ {
+			for dy := -reach; dy <= reach; dy++ {
+				n := cell{c.x + dx, c.y + dy}
+				cx := (float64(n.x) + 0.5) * size
+				cy := (float64(n.y) + 0.5) * size
+				u := math.Hypot(cx-sp.x, cy-sp.y) / bandwidth
+				if u >= 1 {
+					continue
+				}
+				density[n] += w * (1 - u*u) * (1 - u*u)
+			}
+		}
+	}
+
+	var peak float64
+	for _, d := range density {
+		peak = math.Max(peak, d)
+	}
+	hot := make(map[cell]bool)
+	for c, d := range density {
+		if d >= k.Threshold*peak {
+			hot[c] = true
+		}
+	}
+
+	// label connected hot cells.
+	labels := make(map[cell]int)
+	var label int
+	for c := range hot {
+		if labels[c] != 0 {
+			continue
+		}
+		label++
+		labels[c] = label
+		stack := []cell{c}
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					n := cell{cur.x + dx, cur.y + dy}
+					if hot[n] && labels[n] == 0 {
+						labels[n] = label
+						stack = append(stack, n)
+					}
+				}
+			}
+		}
+	}
+
+	groups := make([][]*snapPoint, label)
+	for _, sp := range points {
+		if l := labels[cellOf(sp.x, sp.y)]; l > 0 {
+			groups[l-1] = append(groups[l-1], sp)
+		}
+	}
+
+	hotspots := make([]Hotspot, 0, label)
+	for _, g := range groups {
+		if len(g) > 0 {
+			hotspots = append(hotspots, newHotspot(crimes, g))
+		}
+	}
+	sortHotspots(hotspots)
+	return hotspots
+}
+
+// newHotspot summarises the crimes at the provided snap points.
+func newHotspot(crimes []Crime, points []*snapPoint) Hotspot {
+	h := Hotspot{Categories: make(map[string]int)}
+	var lat, lng float64
+	locations := make([]Point, 0, len(points))
+	for _, sp := range points {
+		n := float64(len(sp.members))
+		lat += sp.point.Latitude * n
+		lng += sp.point.Longitude * n
+		locations = append(locations, sp.point)
+		for _, i := range sp.members {
+			h.Members = append(h.Members, i)
+			h.Categories[crimes[i].Category]++
+		}
+	}
+	sort.Ints(h.Members)
+
+	h.Count = len(h.Members)
+	h.Centroid = Point{Latitude: lat / float64(h.Count), Longitude: lng / float64(h.Count)}
+	h.Hull = convexHull(locations)
+	for category, n := range h.Categories {
+		if n > h.Categories[h.Category] || (n == h.Categories[h.Category] && category < h.Category) {
+			h.Category = category
+		}
+	}
+	return h
+}
+
+func sortHotspots(hotspots []Hotspot) {
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		return hotspots[i].Count > hotspots[j].Count
+	})
+}
+
+// HotspotsGeoJSON returns a GeoJSON feature collection holding a point for
+// each located crime and a polygon for the hull of each hotspot. Crime
+// features have a "hotspot" property holding the index of their hotspot, or -1
+// if they do not belong to one.
+func HotspotsGeoJSON(crimes []Crime, hotspots []Hotspot) ([]byte, error) {
+	membership := make(map[int]int)
+	for i, h := range hotspots {
+		for _, m := range h.Members {
+			membership[m] = i
+		}
+	}
+
+	var features []Feature
+	for i, h := range hotspots {
+		geometry := pointGeometry(h.Centroid)
+		if len(h.Hull) >= 3 {
+			geometry = polygonGeometry(h.Hull)
+		}
+		features = append(features, Feature{
+			Type:     "Feature",
+			Geometry: geometry,
+			Properties: map[string]interface{}{
+				"hotspot":    i,
+				"count":      h.Count,
+				"category":   h.Category,
+				"categories": h.Categories,
+				"centroid":   position(h.Centroid),
+			},
+		})
+	}
+
+	for i, c := range crimes {
+		p, err := c.Location.Point()
+		if err != nil {
+			continue
+		}
+		hotspot, ok := membership[i]
+		if !ok {
+			hotspot = -1
+		}
+		features = append(features, Feature{
+			Type:     "Feature",
+			Geometry: pointGeometry(p),
+			Properties: map[string]interface{}{
+				"id":            c.ID,
+				"persistent_id": c.PersistentID,
+				"category":      c.Category,
+				"month":         c.Month,
+				"street":        c.Location.Street.Name,
+				"hotspot":       hotspot,
+			},
+		})
+	}
+
+	return json.Marshal(NewFeatureCollection(features...))
+}
@@ -0,0 +1,129 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// crimeAt returns a crime of category located at lat, lng.
+func crimeAt(category string, lat, lng float64) Crime {
+	c := Crime{Category: category, Month: "2017-01"}
+	c.Location.Latitude = fmt.Sprint(lat)
+	c.Location.Longitude = fmt.Sprint(lng)
+	return c
+}
+
+// hotspotCrimes returns a dense cluster of burglaries around three adjacent
+// snap points, a smaller cluster of drugs offences 5km away and some isolated
+// crimes.
+func hotspotCrimes() []Crime {
+	var crimes []Crime
+	for i := 0; i < 10; i++ {
+		crimes = append(crimes,
+			crimeAt("burglary", 52.6340, -1.1330),
+			crimeAt("burglary", 52.6345, -1.1335),
+			crimeAt("vehicle-crime", 52.6350, -1.1330),
+		)
+	}
+	for i := 0; i < 6; i++ {
+		crimes = append(crimes, crimeAt("drugs", 52.6790, -1.1330))
+	}
+	crimes = append(crimes,
+		crimeAt("robbery", 52.6000, -1.2000),
+		crimeAt("robbery", 52.7000, -1.0000),
+		Crime{Category: "other-crime"},
+	)
+	return crimes
+}
+
+func TestDBSCAN_Hotspots(t *testing.T) {
+	crimes := hotspotCrimes()
+	hotspots := (&DBSCAN{Radius: 150, MinCrimes: 5}).Hotspots(crimes)
+	if len(hotspots) != 2 {
+		t.Fatalf("DBSCAN.Hotspots returned %d hotspots, want 2: %v", len(hotspots), hotspots)
+	}
+
+	h := hotspots[0]
+	if h.Count != 30 || h.Category != "burglary" || len(h.Hull) != 3 {
+		t.Errorf("DBSCAN.Hotspots returned %v, want 30 burglary-dominated crimes with a triangular hull", h)
+	}
+	want := map[string]int{"burglary": 20, "vehicle-crime": 10}
+	if !reflect.DeepEqual(h.Categories, want) {
+		t.Errorf("DBSCAN.Hotspots categories are %v, want %v", h.Categories, want)
+	}
+	if hotspots[1].Count != 6 || hotspots[1].Category != "drugs" {
+		t.Errorf("DBSCAN.Hotspots returned %v, want 6 drugs crimes", hotspots[1])
+	}
+
+	if got := (&DBSCAN{Radius: 0, MinCrimes: 5}).Hotspots(crimes); !reflect.DeepEqual(got, (&DBSCAN{Radius: 250, MinCrimes: 5}).Hotspots(crimes)) {
+		t.Errorf("DBSCAN.Hotspots with no radius returned %v, want the default radius", got)
+	}
+	if got := (&DBSCAN{Radius: 150}).Hotspots(crimes); !reflect.DeepEqual(got, (&DBSCAN{Radius: 150, MinCrimes: 20}).Hotspots(crimes)) {
+		t.Errorf("DBSCAN.Hotspots with no minimum returned %v, want the default minimum", got)
+	}
+
+	// a single snap point below the threshold is noise.
+	hotspots = (&DBSCAN{Radius: 150, MinCrimes: 7}).Hotspots(crimes)
+	if len(hotspots) != 1 {
+		t.Errorf("DBSCAN.Hotspots returned %d hotspots, want 1", len(hotspots))
+	}
+}
+
+func TestKernelDensity_Hotspots(t *testing.T) {
+	crimes := hotspotCrimes()
+	hotspots := NewKernelDensity().Hotspots(crimes)
+	if len(hotspots) != 1 {
+		t.Fatalf("KernelDensity.Hotspots returned %d hotspots, want 1: %v", len(hotspots), hotspots)
+	}
+	if hotspots[0].Count != 30 || hotspots[0].Category != "burglary" {
+		t.Errorf("KernelDensity.Hotspots returned %v, want 30 burglary-dominated crimes", hotspots[0])
+	}
+
+	// non-positive sizes fall back to the defaults.
+	defaulted := (&KernelDensity{CellSize: -1, Threshold: 0.5}).Hotspots(crimes)
+	if !reflect.DeepEqual(defaulted, hotspots) {
+		t.Errorf("KernelDensity.Hotspots with invalid sizes returned %v, want %v", defaulted, hotspots)
+	}
+
+	if hotspots := NewKernelDensity().Hotspots(nil); len(hotspots) != 0 {
+		t.Errorf("KernelDensity.Hotspots returned %v for no crimes", hotspots)
+	}
+}
+
+func TestHotspotsGeoJSON(t *testing.T) {
+	crimes := hotspotCrimes()
+	hotspots := (&DBSCAN{Radius: 150, MinCrimes: 5}).Hotspots(crimes)
+
+	b, err := HotspotsGeoJSON(crimes, hotspots)
+	if err != nil {
+		t.Fatalf("HotspotsGeoJSON returned error: '%s'", err)
+	}
+
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type string
+			}
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+
+	// two hotspots plus every crime with a location.
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2+len(crimes)-1 {
+		t.Fatalf("HotspotsGeoJSON returned %d features, want %d", len(fc.Features), 2+len(crimes)-1)
+	}
+	if fc.Features[0].Geometry.Type != "Polygon" || fc.Features[1].Geometry.Type != "Point" {
+		t.Errorf("HotspotsGeoJSON returned hotspot geometries %v and %v, want Polygon and Point",
+			fc.Features[0].Geometry.Type, fc.Features[1].Geometry.Type)
+	}
+	last := fc.Features[len(fc.Features)-1]
+	if last.Properties["hotspot"] != float64(-1) || last.Properties["category"] != "robbery" {
+		t.Errorf("HotspotsGeoJSON returned crime properties %v, want a robbery outside any hotspot", last.Properties)
+	}
+}
@@ -0,0 +1,241 @@
+package ukpolice
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Layouts of UTC date-times and of dates in iCalendar.
+const (
+	icalTimeLayout = "20060102T150405Z"
+	icalDateLayout = "20060102"
+)
+
+// CalendarEvent is a neighbourhood event with the neighbourhood holding it.
+type CalendarEvent struct {
+	Force         string             `json:"force"`
+	Neighbourhood string             `json:"neighbourhood"`
+	Event         NeighbourhoodEvent `json:"event"`
+	// Key identifies the occurrence of the event within its neighbourhood
+	// across refreshes of the data. The API gives events no ID, so if Key is
+	// empty the type and start of the event are used, which survive a change
+	// of title, description or venue and differ between the meetings of a
+	// series.
+	Key string `json:"key,omitempty"`
+}
+
+func (e CalendarEvent) key() string {
+	if e.Key != "" {
+		return e.Key
+	}
+	start := strings.TrimSpace(e.Event.StartDate)
+	if !e.Event.Start.IsZero() {
+		start = e.Event.Start.UTC().Format(time.RFC3339)
+	}
+	return strings.Join([]string{e.Event.Type, start}, "\x00")
+}
+
+// UID returns an identifier for the event that is stable across refreshes of
+// the data, derived from its force, neighbourhood and key.
+func (e CalendarEvent) UID() string {
+	return e.uid(e.key())
+}
+
+func (e CalendarEvent) uid(key string) string {
+	h := sha1.New()
+	for _, s := range []string{e.Force, e.Neighbourhood, key} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "@ukpolice"
+}
+
+// allDay reports whether the event gives dates without a time of day.
+func (e CalendarEvent) allDay() bool {
+	_, err := time.Parse("2006-01-02", strings.TrimSpace(e.Event.StartDate))
+	return err == nil
+}
+
+// Calendar is a set of neighbourhood events that can be written in the
+// iCalendar format described by RFC 5545.
+type Calendar struct {
+	Name   string          `json:"name"`
+	Events []CalendarEvent `json:"events"`
+	// Stamp is written as the DTSTAMP of every event. The time of writing is
+	// used if it is zero.
+	Stamp time.Time `json:"-"`
+}
+
+// NewNeighbourhoodCalendar returns a calendar of the events of one
+// neighbourhood.
+func NewNeighbourhoodCalendar(force string, neighbourhood Neighbourhood, events []NeighbourhoodEvent) *Calendar {
+	name := neighbourhood.Name
+	if name == "" {
+		name = neighbourhood.ID
+	}
+	c := &Calendar{Name: name}
+	for _, e := range events {
+		c.Events = append(c.Events, CalendarEvent{Force: force, Neighbourhood: neighbourhood.ID, Event: e})
+	}
+	return c
+}
+
+// NewForceCalendar returns a calendar of the events of every neighbourhood in
+// a force crawl.
+func NewForceCalendar(crawl *ForceCrawl) *Calendar {
+	c := &Calendar{Name: crawl.Force}
+	for _, d := range crawl.Neighbourhoods {
+		for _, e := range d.Events {
+			c.Events = append(c.Events, CalendarEvent{Force: crawl.Force, Neighbourhood: d.Neighbourhood.ID, Event: e})
+		}
+	}
+	return c
+}
+
+// Encode writes the calendar to w. Events without a recognised start time
+// are omitted and events are ordered by start time then UID, so unchanged
+// data produces an unchanged calendar apart from DTSTAMP. Events given as
+// dates are written as all-day events. An event repeated with the same UID
+// and title is written once, and events sharing a UID with different titles,
+// such as two events of a type starting together, have their titles added
+// to their keys to tell them apart.
+func (c *Calendar) Encode(w io.Writer) error {
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	var events []CalendarEvent
+	for _, e := range c.Events {
+		if !e.Event.Start.IsZero() {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].Event.Start, events[j].Event.Start
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return events[i].UID() < events[j].UID()
+	})
+
+	iw := &contentWriter{w: bufio.NewWriter(w)}
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", "-//go-ukpolice//Neighbourhood events//EN")
+	iw.line("CALSCALE", "GREGORIAN")
+	iw.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		iw.line("X-WR-CALNAME", icalText(c.Name))
+	}
+
+	titles := make(map[string]map[string]bool)
+	for _, e := range events {
+		uid := e.UID()
+		if titles[uid] == nil {
+			titles[uid] = make(map[string]bool)
+		}
+		titles[uid][e.Event.Title] = true
+	}
+	seen := make(map[[2]string]bool)
+	for _, e := range events {
+		uid := e.UID()
+		o := [2]string{uid, e.Event.Title}
+		if seen[o] {
+			continue
+		}
+		seen[o] = true
+		if len(titles[uid]) > 1 {
+			uid = e.uid(e.key() + "\x00" + e.Event.Title)
+		}
+
+		iw.line("BEGIN", "VEVENT")
+		iw.line("UID", uid)
+		iw.line("DTSTAMP", stamp.UTC().Format(icalTimeLayout))
+		if e.allDay() {
+			// DTEND is exclusive, so an event ending on a date ends the day
+			// after.
+			iw.line("DTSTART;VALUE=DATE", e.Event.Start.Format(icalDateLayout))
+			if !e.Event.End.Before(e.Event.Start) {
+				iw.line("DTEND;VALUE=DATE", e.Event.End.AddDate(0, 0, 1).Format(icalDateLayout))
+			}
+		} else {
+			iw.line("DTSTART", e.Event.Start.UTC().Format(icalTimeLayout))
+			if e.Event.End.After(e.Event.Start) {
+				iw.line("DTEND", e.Event.End.UTC().Format(icalTimeLayout))
+			}
+		}
+		iw.line("SUMMARY", icalText(e.Event.Title))
+		if d := e.Event.DescriptionText(); d != "" {
+			iw.line("DESCRIPTION", icalText(d))
+		}
+		if e.Event.Address != "" {
+			iw.line("LOCATION", icalText(e.Event.Address))
+		}
+		if e.Event.Type != "" {
+			iw.line("CATEGORIES", icalText(e.Event.Type))
+		}
+		for _, contact := range e.Event.ContactDetails.Contacts() {
+			if contact.Kind == ContactEmail || contact.Kind == ContactTelephone {
+				iw.line("CONTACT", icalText(contact.Value))
+			}
+		}
+		iw.line("END", "VEVENT")
+	}
+	iw.line("END", "VCALENDAR")
+
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
+
+// contentWriter writes the content lines of iCalendar and vCard data, folding
+// them at 75 octets.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (iw *contentWriter) line(name, value string) {
+	if iw.err != nil {
+		return
+	}
+	_, iw.err = iw.w.WriteString(foldLine(name+":"+value) + "\r\n")
+}
+
+// foldLine splits s into lines of at most 75 octets joined by CRLF and a
+// space, without splitting UTF-8 sequences.
+func foldLine(s string) string {
+	const limit = 75
+	var b strings.Builder
+	n := 0
+	max := limit
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if n+size > max {
+			b.WriteString("\r\n ")
+			n = 0
+			max = limit - 1 // the leading space counts towards the limit.
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
+
+// icalText escapes s for use as an iCalendar TEXT value.
+var icalText = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+).Replace
@@ -0,0 +1,168 @@
+package ukpolice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar_Encode(t *testing.T) {
+	events := []NeighbourhoodEvent{
+		{
+			Title:          "Drop In Beat Surgery",
+			Description:    "<p>Come and meet your local team, ask questions; raise concerns.</p>",
+			Address:        "Nagarjuna Buddhist Centre, 17 Guildhall Lane",
+			Type:           "meeting",
+			StartDate:      "2016-09-17T12:00:00",
+			EndDate:        "2016-09-17T14:00:00",
+			Start:          time.Date(2016, 9, 17, 12, 0, 0, 0, londonLocation),
+			End:            time.Date(2016, 9, 17, 14, 0, 0, 0, londonLocation),
+			ContactDetails: ContactDetails{"email": "nc04@leicestershire.pnn.police.uk"},
+		},
+		{Title: "Undated"},
+	}
+	c := NewNeighbourhoodCalendar("leicestershire", Neighbourhood{ID: "NC04", Name: "City Centre"}, events)
+	c.Stamp = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		t.Fatalf("Calendar.Encode returned error: '%s'", err)
+	}
+	got := buf.String()
+
+	uid := CalendarEvent{Force: "leicestershire", Neighbourhood: "NC04", Event: events[0]}.UID()
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//go-ukpolice//Neighbourhood events//EN\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"METHOD:PUBLISH\r\n" +
+		"X-WR-CALNAME:City Centre\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:" + uid + "\r\n" +
+		"DTSTAMP:20170101T000000Z\r\n" +
+		"DTSTART:20160917T110000Z\r\n" +
+		"DTEND:20160917T130000Z\r\n" +
+		"SUMMARY:Drop In Beat Surgery\r\n" +
+		"DESCRIPTION:Come and meet your local team\\, ask questions\\; raise concerns.\r\n" +
+		"LOCATION:Nagarjuna Buddhist Centre\\, 17 Guildhall Lane\r\n" +
+		"CATEGORIES:meeting\r\n" +
+		"CONTACT:nc04@leicestershire.pnn.police.uk\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if got != want {
+		t.Errorf("Calendar.Encode wrote\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCalendar_EncodeAllDayAndSeries(t *testing.T) {
+	day := NeighbourhoodEvent{Title: "Open day", Type: "open-day", StartDate: "2016-09-17", EndDate: "2016-09-18",
+		Start: time.Date(2016, 9, 17, 0, 0, 0, 0, londonLocation), End: time.Date(2016, 9, 18, 0, 0, 0, 0, londonLocation)}
+	first := NeighbourhoodEvent{Title: "Surgery", Type: "meeting", StartDate: "2016-09-20T12:00:00",
+		Start: time.Date(2016, 9, 20, 12, 0, 0, 0, londonLocation)}
+	second := first
+	second.StartDate, second.Start = "2016-09-27T12:00:00", first.Start.AddDate(0, 0, 7)
+
+	c := NewNeighbourhoodCalendar("leicestershire", Neighbourhood{ID: "NC04"}, []NeighbourhoodEvent{second, day, first, first})
+	c.Stamp = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		t.Fatalf("Calendar.Encode returned error: '%s'", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{"DTSTART;VALUE=DATE:20160917\r\n", "DTEND;VALUE=DATE:20160919\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Calendar.Encode output does not contain %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("Calendar.Encode wrote %d events, want 3", n)
+	}
+	for _, e := range []NeighbourhoodEvent{first, second} {
+		uid := CalendarEvent{Force: "leicestershire", Neighbourhood: "NC04", Event: e}.UID()
+		if !strings.Contains(got, "UID:"+uid+"\r\n") {
+			t.Errorf("Calendar.Encode output does not contain UID %s:\n%s", uid, got)
+		}
+	}
+}
+
+func TestCalendar_EncodeSeriesStable(t *testing.T) {
+	var series []NeighbourhoodEvent
+	for d := 1; d <= 15; d += 7 {
+		start := time.Date(2017, 1, d, 19, 0, 0, 0, londonLocation)
+		series = append(series, NeighbourhoodEvent{Title: "Surgery", Type: "meeting",
+			StartDate: start.Format("2006-01-02T15:04:05"), Start: start})
+	}
+	uids := func(events []NeighbourhoodEvent) []string {
+		c := NewNeighbourhoodCalendar("leicestershire", Neighbourhood{ID: "NC04"}, events)
+		c.Stamp = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		var buf bytes.Buffer
+		if err := c.Encode(&buf); err != nil {
+			t.Fatalf("Calendar.Encode returned error: '%s'", err)
+		}
+		var uids []string
+		for _, line := range strings.Split(buf.String(), "\r\n") {
+			if strings.HasPrefix(line, "UID:") {
+				uids = append(uids, line)
+			}
+		}
+		return uids
+	}
+
+	all, later := uids(series), uids(series[1:])
+	if len(all) != 3 || len(later) != 2 {
+		t.Fatalf("Calendar.Encode wrote UIDs %v and %v, want 3 and 2", all, later)
+	}
+	if all[0] == all[1] || later[0] != all[1] || later[1] != all[2] {
+		t.Errorf("UIDs changed when the first event dropped out: %v then %v", all, later)
+	}
+
+	clash := series[0]
+	clash.Title = "Street meeting"
+	if got := uids([]NeighbourhoodEvent{series[0], clash}); len(got) != 2 || got[0] == got[1] {
+		t.Errorf("Calendar.Encode wrote UIDs %v for different events starting together", got)
+	}
+}
+
+func TestCalendarEvent_UID(t *testing.T) {
+	e := CalendarEvent{Force: "leicestershire", Neighbourhood: "NC04", Event: NeighbourhoodEvent{
+		Title: "Surgery", StartDate: "2016-09-17T12:00:00", Address: "Library"}}
+	edited := e
+	edited.Event.Address = "Town Hall"
+	edited.Event.Title = "Beat surgery"
+	edited.Event.Description = "Bring a friend"
+	if e.UID() != edited.UID() {
+		t.Errorf("CalendarEvent.UID changed with the address, title and description")
+	}
+	moved := e
+	moved.Event.StartDate = "2016-09-24T12:00:00"
+	if e.UID() == moved.UID() {
+		t.Errorf("CalendarEvent.UID is the same for different starts")
+	}
+	keyed := e
+	keyed.Key = "event-1"
+	if e.UID() == keyed.UID() {
+		t.Errorf("CalendarEvent.UID ignored the key")
+	}
+	other := e
+	other.Neighbourhood = "NC66"
+	if e.UID() == other.UID() {
+		t.Errorf("CalendarEvent.UID is the same for different neighbourhoods")
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	s := strings.Repeat("é", 100)
+	for i, line := range strings.Split(foldLine(s), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("line %d does not begin with a space", i)
+		}
+	}
+	if got := strings.Replace(foldLine(s), "\r\n ", "", -1); got != s {
+		t.Errorf("unfolding foldLine(%q) returned %q", s, got)
+	}
+}
@@ -0,0 +1,47 @@
+package ukpolice
+
+import (
+	"fmt"
+	"time"
+)
+
+// monthLayout is the YYYY-MM format used by the data.police.uk API for dates.
+const monthLayout = "2006-01"
+
+// parseMonth parses a month in the format YYYY-MM.
+func parseMonth(month string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q: expected YYYY-MM", month)
+	}
+	return t, nil
+}
+
+// addMonths returns the month n months after the provided month. The provided
+// month must be valid.
+func addMonths(month string, n int) string {
+	t, err := parseMonth(month)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, n, 0).Format(monthLayout)
+}
+
+// monthsBetween returns every month from start to end inclusive. An empty
+// slice is returned if end is before start.
+func monthsBetween(start, end string) ([]string, error) {
+	s, err := parseMonth(start)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseMonth(end)
+	if err != nil {
+		return nil, err
+	}
+
+	var months []string
+	for t := s; !t.After(e); t = t.AddDate(0, 1, 0) {
+		months = append(months, t.Format(monthLayout))
+	}
+	return months, nil
+}
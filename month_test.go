@@ -0,0 +1,42 @@
+package ukpolice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMonthsBetween(t *testing.T) {
+	tt := []struct {
+		name       string
+		start, end string
+		want       []string
+	}{
+		{"Single", "2017-01", "2017-01", []string{"2017-01"}},
+		{"Year boundary", "2016-11", "2017-02", []string{"2016-11", "2016-12", "2017-01", "2017-02"}},
+		{"Reversed", "2017-02", "2017-01", nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := monthsBetween(tc.start, tc.end)
+			if err != nil {
+				t.Fatalf("monthsBetween returned error: '%s'", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("monthsBetween returned %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := monthsBetween("2017-1", "2017-02"); err == nil {
+		t.Errorf("monthsBetween should have returned an error for an invalid month")
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	if got := addMonths("2017-11", 3); got != "2018-02" {
+		t.Errorf("addMonths returned %v, want %v", got, "2018-02")
+	}
+	if got := addMonths("2017-01", -1); got != "2016-12" {
+		t.Errorf("addMonths returned %v, want %v", got, "2016-12")
+	}
+}
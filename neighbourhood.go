@@ -2,7 +2,9 @@ package ukpolice
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // NeighbourhoodService handles communication with the neighbourhood related
@@ -12,7 +14,7 @@ type NeighbourhoodService service
 // Neighbourhood holds details of neighbourhoods.
 type Neighbourhood struct {
 	ForceURL       string              `json:"url_force,omitempty"`
-	ContactDetails map[string]string   `json:"contact_details,omitempty"`
+	ContactDetails ContactDetails      `json:"contact_details,omitempty"`
 	Name           string              `json:"name,omitempty"`
 	Links          []map[string]string `json:"links,omitempty"`
 	Centre         Location            `json:"centre,omitempty"`
@@ -32,10 +34,10 @@ func (n Neighbourhood) String() string {
 
 // NeighbourhoodTeam holds details of neighbourhood teams.
 type NeighbourhoodTeam struct {
-	Bio            string            `json:"bio,omitempty"`
-	ContactDetails map[string]string `json:"contact_details,omitempty"`
-	Name           string            `json:"name,omitempty"`
-	Rank           string            `json:"rank,omitempty"`
+	Bio            string         `json:"bio,omitempty"`
+	ContactDetails ContactDetails `json:"contact_details,omitempty"`
+	Name           string         `json:"name,omitempty"`
+	Rank           string         `json:"rank,omitempty"`
 }
 
 func (n NeighbourhoodTeam) String() string {
@@ -44,20 +46,63 @@ func (n NeighbourhoodTeam) String() string {
 
 // NeighbourhoodEvent holds details of neighbourhood events.
 type NeighbourhoodEvent struct {
-	ContactDetails map[string]string `json:"contact_details,omitempty"`
-	Description    string            `json:"description,omitempty"`
-	Title          string            `json:"title,omitempty"`
-	Address        string            `json:"address,omitempty"`
-	Type           string            `json:"type,omitempty"`
+	ContactDetails ContactDetails `json:"contact_details,omitempty"`
+	Description    string         `json:"description,omitempty"`
+	Title          string         `json:"title,omitempty"`
+	Address        string         `json:"address,omitempty"`
+	Type           string         `json:"type,omitempty"`
 
 	StartDate string `json:"start_date,omitempty"`
 	EndDate   string `json:"end_date,omitempty"`
+
+	// Start and End are StartDate and EndDate parsed in Europe/London time.
+	// They are zero if the dates are missing or not recognised.
+	Start time.Time `json:"-"`
+	End   time.Time `json:"-"`
 }
 
 func (n NeighbourhoodEvent) String() string {
 	return Stringify(n)
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface, parsing the start
+// and end dates.
+func (n *NeighbourhoodEvent) UnmarshalJSON(b []byte) error {
+	type event NeighbourhoodEvent
+	var e event
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	*n = NeighbourhoodEvent(e)
+	n.Start = parseEventTime(n.StartDate)
+	n.End = parseEventTime(n.EndDate)
+	return nil
+}
+
+// eventTimeLayouts are the layouts of event dates, which are local times
+// unless they carry an offset.
+var eventTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func parseEventTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(londonLocation)
+	}
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, londonLocation); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // NeighbourhoodPriorities holds details of neighbourhood priorities.
 type NeighbourhoodPriorities struct {
 	Action string `json:"action,omitempty"`
@@ -73,6 +118,9 @@ func (n NeighbourhoodPriorities) String() string {
 
 // GetNeighbourhoods returns a the neighbourhood details for a given police force.
 func (n *NeighbourhoodService) GetNeighbourhoods(ctx context.Context, force string) ([]Neighbourhood, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/neighbourhoods", force)
 
 	req, err := n.api.NewRequest("GET", u, nil)
@@ -92,6 +140,9 @@ func (n *NeighbourhoodService) GetNeighbourhoods(ctx context.Context, force stri
 // GetSpecificNeighbourhood returns the details of a specific neighbourhood given
 // a police force and neighbourhood ID
 func (n *NeighbourhoodService) GetSpecificNeighbourhood(ctx context.Context, force, ID string) (*Neighbourhood, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/%s", force, ID)
 
 	req, err := n.api.NewRequest("GET", u, nil)
@@ -111,6 +162,9 @@ func (n *NeighbourhoodService) GetSpecificNeighbourhood(ctx context.Context, for
 // GetNeighbourhoodBoundary returns a list of latitude/longitude pairs that make
 // up the boundary of a neighbourhood.
 func (n *NeighbourhoodService) GetNeighbourhoodBoundary(ctx context.Context, force, NeighbourhoodID string) ([]Location, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/%s/boundary", force, NeighbourhoodID)
 
 	req, err := n.api.NewRequest("GET", u, nil)
@@ -128,6 +182,9 @@ func (n *NeighbourhoodService) GetNeighbourhoodBoundary(ctx context.Context, for
 
 // GetNeighbourhoodTeam returns a list of team information for a given force and neighbourhood.
 func (n *NeighbourhoodService) GetNeighbourhoodTeam(ctx context.Context, force, NeighbourhoodID string) ([]NeighbourhoodTeam, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/%s/people", force, NeighbourhoodID)
 
 	req, err := n.api.NewRequest("GET", u, nil)
@@ -145,6 +202,9 @@ func (n *NeighbourhoodService) GetNeighbourhoodTeam(ctx context.Context, force,
 
 // GetNeighbourhoodEvents returns a list of events information for a given force and neighbourhood.
 func (n *NeighbourhoodService) GetNeighbourhoodEvents(ctx context.Context, force, NeighbourhoodID string) ([]NeighbourhoodEvent, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/%s/events", force, NeighbourhoodID)
 
 	req, err := n.api.NewRequest("GET", u, nil)
@@ -162,6 +222,9 @@ func (n *NeighbourhoodService) GetNeighbourhoodEvents(ctx context.Context, force
 
 // GetNeighbourhoodPriorities returns a list of priorities for a given force and neighbourhood
 func (n *NeighbourhoodService) GetNeighbourhoodPriorities(ctx context.Context, force, NeighbourhoodID string) ([]NeighbourhoodPriorities, *Response, error) {
+	if err := n.api.Forces.Validate(force); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("%s/%s/priorities", force, NeighbourhoodID)
 
 	req, err := n.api.NewRequest("GET", u, nil)
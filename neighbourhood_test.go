@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // Neighbourhoods
@@ -277,6 +278,8 @@ func TestNeighbourhoodService_GetNeighbourhoodEvents(t *testing.T) {
 			Type:           "meeting",
 			StartDate:      "2016-09-17T12:00:00",
 			EndDate:        "2016-09-17T14:00:00",
+			Start:          time.Date(2016, 9, 17, 12, 0, 0, 0, londonLocation),
+			End:            time.Date(2016, 9, 17, 14, 0, 0, 0, londonLocation),
 		},
 	}
 
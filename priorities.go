@@ -0,0 +1,310 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PriorityChangeType is the kind of change made to a neighbourhood priority.
+type PriorityChangeType string
+
+// Kinds of priority change.
+const (
+	PriorityNew     PriorityChangeType = "new"
+	PriorityUpdated PriorityChangeType = "updated"
+	PriorityRetired PriorityChangeType = "retired"
+)
+
+// PrioritySnapshot holds the priorities of a neighbourhood at a point in time.
+type PrioritySnapshot struct {
+	Force         string                    `json:"force"`
+	Neighbourhood string                    `json:"neighbourhood"`
+	Taken         time.Time                 `json:"taken"`
+	Priorities    []NeighbourhoodPriorities `json:"priorities"`
+}
+
+func (p PrioritySnapshot) String() string {
+	return Stringify(p)
+}
+
+// PriorityChange describes a priority that was added, updated or retired
+// between two snapshots. Previous is nil for new priorities and Current is
+// nil for retired ones.
+type PriorityChange struct {
+	Type          PriorityChangeType       `json:"type"`
+	Force         string                   `json:"force"`
+	Neighbourhood string                   `json:"neighbourhood"`
+	Time          time.Time                `json:"time"`
+	Previous      *NeighbourhoodPriorities `json:"previous,omitempty"`
+	Current       *NeighbourhoodPriorities `json:"current,omitempty"`
+}
+
+func (p PriorityChange) String() string {
+	return Stringify(p)
+}
+
+func (p PrioritySnapshot) ref() NeighbourhoodRef {
+	return NeighbourhoodRef{Force: p.Force, Neighbourhood: p.Neighbourhood}
+}
+
+func (p PrioritySnapshot) taken() time.Time {
+	return p.Taken
+}
+
+// copy returns p with its own slice of priorities.
+func (p PrioritySnapshot) copy() PrioritySnapshot {
+	p.Priorities = append([]NeighbourhoodPriorities{}, p.Priorities...)
+	return p
+}
+
+// PriorityTracker stores snapshots of neighbourhood priorities so that
+// changes to them can be followed over time. It is safe for concurrent use.
+type PriorityTracker struct {
+	store *snapshotStore
+}
+
+// NewPriorityTracker returns an empty PriorityTracker.
+func NewPriorityTracker() *PriorityTracker {
+	return &PriorityTracker{store: newSnapshotStore()}
+}
+
+// Record adds a snapshot of the priorities of a neighbourhood taken at the
+// provided time and returns the changes since the previous snapshot. Every
+// priority in the first snapshot of a neighbourhood is new. A snapshot
+// identical to the previous one is not stored.
+func (t *PriorityTracker) Record(force, neighbourhood string, taken time.Time, priorities []NeighbourhoodPriorities) []PriorityChange {
+	current := PrioritySnapshot{
+		Force:         force,
+		Neighbourhood: neighbourhood,
+		Taken:         taken,
+		Priorities:    priorities,
+	}.copy()
+
+	var changes []PriorityChange
+	t.store.record(current, func(previous snapshot) bool {
+		var p *PrioritySnapshot
+		if previous != nil {
+			ps := previous.(PrioritySnapshot)
+			p = &ps
+		}
+		changes = diffPriorities(p, current)
+		return len(changes) > 0
+	})
+	return changes
+}
+
+// RecordCrawl records the priorities of every neighbourhood in a force crawl
+// whose priorities were fetched, returning the changes.
+func (t *PriorityTracker) RecordCrawl(crawl *ForceCrawl, taken time.Time) []PriorityChange {
+	var changes []PriorityChange
+	for _, d := range crawl.Neighbourhoods {
+		if _, failed := d.Errors[CrawlPriorities]; failed {
+			continue
+		}
+		changes = append(changes, t.Record(crawl.Force, d.Neighbourhood.ID, taken, d.Priorities)...)
+	}
+	return changes
+}
+
+// Poll fetches the current priorities of a neighbourhood and records them.
+func (t *PriorityTracker) Poll(ctx context.Context, n *NeighbourhoodService, force, neighbourhood string) ([]PriorityChange, error) {
+	priorities, _, err := n.GetNeighbourhoodPriorities(ctx, force, neighbourhood)
+	if err != nil {
+		return nil, err
+	}
+	return t.Record(force, neighbourhood, time.Now(), priorities), nil
+}
+
+// Snapshots returns copies of the stored snapshots of a neighbourhood, oldest
+// first.
+func (t *PriorityTracker) Snapshots(force, neighbourhood string) []PrioritySnapshot {
+	var snapshots []PrioritySnapshot
+	for _, s := range t.store.snapshots(NeighbourhoodRef{Force: force, Neighbourhood: neighbourhood}) {
+		snapshots = append(snapshots, s.(PrioritySnapshot).copy())
+	}
+	return snapshots
+}
+
+// Timeline returns every change to the priorities of a neighbourhood, oldest
+// first.
+func (t *PriorityTracker) Timeline(force, neighbourhood string) []PriorityChange {
+	var changes []PriorityChange
+	var previous *PrioritySnapshot
+	for _, s := range t.store.snapshots(NeighbourhoodRef{Force: force, Neighbourhood: neighbourhood}) {
+		current := s.(PrioritySnapshot)
+		changes = append(changes, diffPriorities(previous, current)...)
+		previous = &current
+	}
+	return changes
+}
+
+// ForceTimeline returns every change to the priorities of the neighbourhoods
+// of a force ordered by time and neighbourhood.
+func (t *PriorityTracker) ForceTimeline(force string) []PriorityChange {
+	var changes []PriorityChange
+	for _, ref := range t.store.refs(force) {
+		changes = append(changes, t.Timeline(ref.Force, ref.Neighbourhood)...)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].Time.Equal(changes[j].Time) {
+			return changes[i].Time.Before(changes[j].Time)
+		}
+		return changes[i].Neighbourhood < changes[j].Neighbourhood
+	})
+	return changes
+}
+
+// diffPriorities returns the changes from previous, which may be nil, to
+// current. A priority is matched with an earlier one by its issue date and
+// issue, then by issue date and a similar issue and finally by issue alone,
+// so an edited issue or a new action is reported as an update while an
+// unrelated priority raised on the same date is new. Changes hold copies of the
+// priorities, so they do not share memory with the snapshots.
+func diffPriorities(previous *PrioritySnapshot, current PrioritySnapshot) []PriorityChange {
+	change := func(t PriorityChangeType, p, c *NeighbourhoodPriorities) PriorityChange {
+		pc := PriorityChange{
+			Type:          t,
+			Force:         current.Force,
+			Neighbourhood: current.Neighbourhood,
+			Time:          current.Taken,
+		}
+		if p != nil {
+			previous := *p
+			pc.Previous = &previous
+		}
+		if c != nil {
+			current := *c
+			pc.Current = &current
+		}
+		return pc
+	}
+
+	var before []NeighbourhoodPriorities
+	if previous != nil {
+		before = previous.Priorities
+	}
+	after := current.Priorities
+	matchedBefore := make([]bool, len(before))
+	match := make([]int, len(after))
+	for i := range match {
+		match[i] = -1
+	}
+
+	passes := []func(a, b NeighbourhoodPriorities) bool{
+		func(a, b NeighbourhoodPriorities) bool {
+			return a.IssueDate == b.IssueDate && priorityText(a.Issue) == priorityText(b.Issue)
+		},
+		func(a, b NeighbourhoodPriorities) bool {
+			return a.IssueDate != "" && a.IssueDate == b.IssueDate && similarIssues(a.Issue, b.Issue)
+		},
+		func(a, b NeighbourhoodPriorities) bool {
+			return priorityText(a.Issue) != "" && priorityText(a.Issue) == priorityText(b.Issue)
+		},
+	}
+	for _, same := range passes {
+		for i, a := range after {
+			if match[i] >= 0 {
+				continue
+			}
+			for j, b := range before {
+				if !matchedBefore[j] && same(a, b) {
+					match[i], matchedBefore[j] = j, true
+					break
+				}
+			}
+		}
+	}
+
+	var changes []PriorityChange
+	for i := range after {
+		c := &after[i]
+		if match[i] < 0 {
+			changes = append(changes, change(PriorityNew, nil, c))
+		} else if p := &before[match[i]]; !reflect.DeepEqual(*p, *c) {
+			changes = append(changes, change(PriorityUpdated, p, c))
+		}
+	}
+	for j := range before {
+		if !matchedBefore[j] {
+			changes = append(changes, change(PriorityRetired, &before[j], nil))
+		}
+	}
+	return changes
+}
+
+// priorityText returns the plain text of an issue or action for comparison.
+func priorityText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(HTMLToText(s)), " "))
+}
+
+// similarIssues reports whether more than half the words of the shorter
+// issue appear in the other. Words of fewer than four letters, which are
+// mostly articles and prepositions, are ignored.
+func similarIssues(a, b string) bool {
+	words := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, w := range strings.FieldsFunc(priorityText(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if utf8.RuneCountInString(w) >= 4 {
+				set[w] = true
+			}
+		}
+		return set
+	}
+	wa, wb := words(a), words(b)
+	if len(wa) > len(wb) {
+		wa, wb = wb, wa
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	return 2*shared > len(wa)
+}
+
+// Encode writes every snapshot in the tracker to w as JSON.
+func (t *PriorityTracker) Encode(w io.Writer) error {
+	var snapshots []PrioritySnapshot
+	for _, s := range t.store.all() {
+		snapshots = append(snapshots, s.(PrioritySnapshot))
+	}
+	return json.NewEncoder(w).Encode(snapshots)
+}
+
+// Decode adds the snapshots written by Encode to the tracker.
+func (t *PriorityTracker) Decode(r io.Reader) error {
+	var decoded []PrioritySnapshot
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return err
+	}
+	snapshots := make([]snapshot, len(decoded))
+	for i, s := range decoded {
+		snapshots[i] = s
+	}
+	t.store.add(snapshots)
+	return nil
+}
+
+// Save writes the tracker to the file at path.
+func (t *PriorityTracker) Save(path string) error {
+	return saveFile(path, t.Encode)
+}
+
+// LoadPriorityTracker reads a tracker saved with Save.
+func LoadPriorityTracker(path string) (*PriorityTracker, error) {
+	t := NewPriorityTracker()
+	if err := loadFile(path, t.Decode); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
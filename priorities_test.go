@@ -0,0 +1,91 @@
+package ukpolice
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// day returns midnight UTC on the dth of January 2017.
+func day(d int) time.Time {
+	return time.Date(2017, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPriorityTracker(t *testing.T) {
+	tracker := NewPriorityTracker()
+
+	parking := NeighbourhoodPriorities{Issue: "<p>Parking</p>", IssueDate: "2016-12-01T00:00:00"}
+	litter := NeighbourhoodPriorities{Issue: "<p>Litter</p>", IssueDate: "2016-12-05T00:00:00"}
+
+	changes := tracker.Record("leicestershire", "NC04", day(1), []NeighbourhoodPriorities{parking, litter})
+	if len(changes) != 2 || changes[0].Type != PriorityNew || changes[1].Type != PriorityNew {
+		t.Errorf("PriorityTracker.Record returned %v, want two new priorities", changes)
+	}
+
+	// unchanged priorities are not stored again.
+	if changes := tracker.Record("leicestershire", "NC04", day(2), []NeighbourhoodPriorities{litter, parking}); len(changes) != 0 {
+		t.Errorf("PriorityTracker.Record returned %v for unchanged priorities", changes)
+	}
+
+	actioned := parking
+	actioned.Action = "<p>Patrols increased</p>"
+	actioned.ActionDate = "2017-01-02T00:00:00"
+	dogs := NeighbourhoodPriorities{Issue: "Dog fouling", IssueDate: "2017-01-03T00:00:00"}
+	changes = tracker.Record("leicestershire", "NC04", day(3), []NeighbourhoodPriorities{actioned, dogs})
+
+	want := []PriorityChange{
+		{Type: PriorityUpdated, Force: "leicestershire", Neighbourhood: "NC04", Time: day(3), Previous: &parking, Current: &actioned},
+		{Type: PriorityNew, Force: "leicestershire", Neighbourhood: "NC04", Time: day(3), Current: &dogs},
+		{Type: PriorityRetired, Force: "leicestershire", Neighbourhood: "NC04", Time: day(3), Previous: &litter},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("PriorityTracker.Record returned %v, want %v", changes, want)
+	}
+
+	if got := len(tracker.Snapshots("leicestershire", "NC04")); got != 2 {
+		t.Errorf("PriorityTracker.Snapshots returned %d snapshots, want 2", got)
+	}
+	timeline := tracker.Timeline("leicestershire", "NC04")
+	if len(timeline) != 5 || !reflect.DeepEqual(timeline[2:], want) {
+		t.Errorf("PriorityTracker.Timeline returned %v", timeline)
+	}
+
+	var buf bytes.Buffer
+	if err := tracker.Encode(&buf); err != nil {
+		t.Fatalf("PriorityTracker.Encode returned error: '%s'", err)
+	}
+	decoded := NewPriorityTracker()
+	if err := decoded.Decode(&buf); err != nil {
+		t.Fatalf("PriorityTracker.Decode returned error: '%s'", err)
+	}
+	if got := decoded.ForceTimeline("leicestershire"); !reflect.DeepEqual(got, timeline) {
+		t.Errorf("decoded PriorityTracker.ForceTimeline returned %v, want %v", got, timeline)
+	}
+
+	// results do not share memory with the tracker.
+	changes[0].Current.Issue = "changed"
+	timeline[0].Current.Issue = "changed"
+	tracker.Snapshots("leicestershire", "NC04")[1].Priorities[0].Issue = "changed"
+	if s := tracker.Snapshots("leicestershire", "NC04"); s[0].Priorities[0].Issue != parking.Issue || s[1].Priorities[0].Issue != actioned.Issue {
+		t.Errorf("PriorityTracker results share memory with its snapshots: %v", s)
+	}
+}
+
+func TestDiffPriorities_editedIssue(t *testing.T) {
+	before := &PrioritySnapshot{Priorities: []NeighbourhoodPriorities{{Issue: "Speeding", IssueDate: "2017-01-01T00:00:00"}}}
+	after := PrioritySnapshot{Priorities: []NeighbourhoodPriorities{{Issue: "Speeding on Main Street", IssueDate: "2017-01-01T00:00:00"}}}
+	changes := diffPriorities(before, after)
+	if len(changes) != 1 || changes[0].Type != PriorityUpdated {
+		t.Errorf("diffPriorities returned %v, want one update", changes)
+	}
+}
+
+func TestDiffPriorities_unrelatedSameDate(t *testing.T) {
+	before := &PrioritySnapshot{Priorities: []NeighbourhoodPriorities{{Issue: "<p>Drugs</p>", IssueDate: "2017-01-01T00:00:00"}}}
+	after := PrioritySnapshot{Priorities: []NeighbourhoodPriorities{{Issue: "<p>Burglary</p>", IssueDate: "2017-01-01T00:00:00"}}}
+	changes := diffPriorities(before, after)
+	if len(changes) != 2 || changes[0].Type != PriorityNew || changes[1].Type != PriorityRetired {
+		t.Errorf("diffPriorities returned %v, want one new and one retired priority", changes)
+	}
+}
@@ -0,0 +1,234 @@
+package ukpolice
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Sources of a neighbourhood population.
+const (
+	PopulationFromAPI      = "api"
+	PopulationFromOverride = "override"
+)
+
+// parsePopulation parses a population such as "7985" or "12,345", ignoring
+// thousands separators and spaces.
+func parsePopulation(s string) (float64, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ',' || r == ' ' {
+			return -1
+		}
+		return r
+	}, s)
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid population %q", s)
+	}
+	return n, nil
+}
+
+// Residents returns the population of the neighbourhood given by the API.
+// It reports false if the population is missing, zero or not a number.
+func (n Neighbourhood) Residents() (int, bool) {
+	p, err := parsePopulation(n.Population)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return int(math.Round(p)), true
+}
+
+// NeighbourhoodPopulation holds the resident population of neighbourhoods,
+// used in place of the populations given by the API.
+type NeighbourhoodPopulation map[NeighbourhoodRef]float64
+
+// ReadNeighbourhoodPopulationCSV reads a population table with a force ID, a
+// neighbourhood ID and a population in each row. A first row whose population
+// is text is skipped as a header and thousands separators are ignored.
+func ReadNeighbourhoodPopulationCSV(r io.Reader) (NeighbourhoodPopulation, error) {
+	population := make(NeighbourhoodPopulation)
+	err := readPopulationCSV(r, 3, nil, func(record []string, n float64) {
+		ref := NeighbourhoodRef{Force: strings.TrimSpace(record[0]), Neighbourhood: strings.TrimSpace(record[1])}
+		population[ref] = n
+	})
+	if err != nil {
+		return nil, err
+	}
+	return population, nil
+}
+
+// readPopulationCSV reads a table with a population in the last of columns
+// columns, calling row with each record and its population. The first record
+// is skipped as a header if its population is text, such as "Population",
+// and header, if not nil, also reports true for it.
+func readPopulationCSV(r io.Reader, columns int, header func(record []string) bool, row func(record []string, n float64)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < columns {
+			return fmt.Errorf("line %d: expected %d columns, got %d", line, columns, len(record))
+		}
+
+		field := strings.TrimSpace(record[columns-1])
+		n, err := parsePopulation(field)
+		if err != nil {
+			if line == 1 && strings.IndexFunc(field, unicode.IsLetter) >= 0 && (header == nil || header(record)) {
+				continue
+			}
+			return fmt.Errorf("line %d: invalid population %q", line, field)
+		}
+		row(record, n)
+	}
+}
+
+// NeighbourhoodRate holds the crimes and stop and searches inside the boundary
+// of a neighbourhood relative to its population.
+type NeighbourhoodRate struct {
+	NeighbourhoodRef
+	Name string `json:"name,omitempty"`
+	// Population is zero and PopulationSource empty if the population of the
+	// neighbourhood is unknown, in which case rates are zero.
+	Population       float64        `json:"population"`
+	PopulationSource string         `json:"population_source,omitempty"`
+	Crimes           int            `json:"crimes"`
+	Searches         int            `json:"searches"`
+	CrimesByCategory map[string]int `json:"crimes_by_category"`
+	// CrimeRate and SearchRate are per 1,000 residents.
+	CrimeRate  float64 `json:"crime_rate"`
+	SearchRate float64 `json:"search_rate"`
+}
+
+func (n NeighbourhoodRate) String() string {
+	return Stringify(n)
+}
+
+// CategoryRate returns the number of crimes of category per 1,000 residents.
+func (n NeighbourhoodRate) CategoryRate(category string) float64 {
+	return perThousand(n.CrimesByCategory[category], n.Population)
+}
+
+func perThousand(count int, population float64) float64 {
+	if population <= 0 {
+		return 0
+	}
+	return 1000 * float64(count) / population
+}
+
+// RateReport holds the rates of each neighbourhood of a force.
+type RateReport struct {
+	Force          string              `json:"force"`
+	Neighbourhoods []NeighbourhoodRate `json:"neighbourhoods"`
+	// Unassigned counts the crimes and searches with a location inside no
+	// neighbourhood boundary.
+	UnassignedCrimes   int `json:"unassigned_crimes"`
+	UnassignedSearches int `json:"unassigned_searches"`
+}
+
+func (r RateReport) String() string {
+	return Stringify(r)
+}
+
+// NeighbourhoodRates assigns crimes and searches to the neighbourhoods of a
+// force crawl whose boundaries contain them and computes rates per 1,000
+// residents. The crawl must include CrawlDetails, for populations, and
+// CrawlBoundary. A population in overrides, which may be nil, replaces the
+// population given by the API. Crimes and searches without a location are
+// ignored, and neighbourhoods are ordered by ID.
+func NeighbourhoodRates(crawl *ForceCrawl, crimes []Crime, searches []Search, overrides NeighbourhoodPopulation) *RateReport {
+	type area struct {
+		ring     []Point
+		min, max Point
+	}
+	report := &RateReport{Force: crawl.Force, Neighbourhoods: make([]NeighbourhoodRate, len(crawl.Neighbourhoods))}
+	areas := make([]area, len(crawl.Neighbourhoods))
+	for i, d := range crawl.Neighbourhoods {
+		rate := NeighbourhoodRate{
+			NeighbourhoodRef: NeighbourhoodRef{Force: crawl.Force, Neighbourhood: d.Neighbourhood.ID},
+			Name:             d.Neighbourhood.Name,
+			CrimesByCategory: make(map[string]int),
+		}
+		if p, ok := overrides[rate.NeighbourhoodRef]; ok && p > 0 {
+			rate.Population, rate.PopulationSource = p, PopulationFromOverride
+		} else if p, ok := d.Neighbourhood.Residents(); ok {
+			rate.Population, rate.PopulationSource = float64(p), PopulationFromAPI
+		}
+		report.Neighbourhoods[i] = rate
+
+		a := area{min: Point{math.Inf(1), math.Inf(1)}, max: Point{math.Inf(-1), math.Inf(-1)}}
+		for _, l := range d.Boundary {
+			if p, err := l.Point(); err == nil {
+				a.ring = append(a.ring, p)
+				a.min = Point{math.Min(a.min.Latitude, p.Latitude), math.Min(a.min.Longitude, p.Longitude)}
+				a.max = Point{math.Max(a.max.Latitude, p.Latitude), math.Max(a.max.Longitude, p.Longitude)}
+			}
+		}
+		areas[i] = a
+	}
+
+	// locate returns the index of the neighbourhood containing l, or -1, and
+	// false if l has no coordinates.
+	locate := func(l Location) (int, bool) {
+		p, err := l.Point()
+		if err != nil {
+			return -1, false
+		}
+		for i, a := range areas {
+			if len(a.ring) >= 3 &&
+				p.Latitude >= a.min.Latitude && p.Latitude <= a.max.Latitude &&
+				p.Longitude >= a.min.Longitude && p.Longitude <= a.max.Longitude &&
+				pointInPolygon(p, a.ring) {
+				return i, true
+			}
+		}
+		return -1, true
+	}
+
+	for _, c := range crimes {
+		i, ok := locate(c.Location)
+		switch {
+		case !ok:
+		case i < 0:
+			report.UnassignedCrimes++
+		default:
+			report.Neighbourhoods[i].Crimes++
+			report.Neighbourhoods[i].CrimesByCategory[c.Category]++
+		}
+	}
+	for _, s := range searches {
+		i, ok := locate(s.Location)
+		switch {
+		case !ok:
+		case i < 0:
+			report.UnassignedSearches++
+		default:
+			report.Neighbourhoods[i].Searches++
+		}
+	}
+
+	for i := range report.Neighbourhoods {
+		r := &report.Neighbourhoods[i]
+		r.CrimeRate = perThousand(r.Crimes, r.Population)
+		r.SearchRate = perThousand(r.Searches, r.Population)
+	}
+	sort.SliceStable(report.Neighbourhoods, func(i, j int) bool {
+		return report.Neighbourhoods[i].Neighbourhood < report.Neighbourhoods[j].Neighbourhood
+	})
+	return report
+}
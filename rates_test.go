@@ -0,0 +1,121 @@
+package ukpolice
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNeighbourhood_Residents(t *testing.T) {
+	for _, tt := range []struct {
+		population string
+		want       int
+		ok         bool
+	}{
+		{"7985", 7985, true},
+		{"12,345", 12345, true},
+		{" 1 024.0", 1024, true},
+		{"0", 0, false},
+		{"", 0, false},
+		{"unknown", 0, false},
+		{"-5", 0, false},
+	} {
+		got, ok := Neighbourhood{Population: tt.population}.Residents()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Residents of %q returned %d, %v, want %d, %v", tt.population, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadNeighbourhoodPopulationCSV(t *testing.T) {
+	csv := `force,neighbourhood,population
+leicestershire,NC04,"10,000"
+leicestershire, NC66, 2500
+`
+	population, err := ReadNeighbourhoodPopulationCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ReadNeighbourhoodPopulationCSV returned error: '%s'", err)
+	}
+	want := NeighbourhoodPopulation{
+		{Force: "leicestershire", Neighbourhood: "NC04"}: 10000,
+		{Force: "leicestershire", Neighbourhood: "NC66"}: 2500,
+	}
+	if !reflect.DeepEqual(population, want) {
+		t.Errorf("ReadNeighbourhoodPopulationCSV returned %v, want %v", population, want)
+	}
+
+	for _, invalid := range []string{"a,b,1\nc,d,many\n", "a,b,1.2.3\nc,d,1\n", "a,b,-1\n"} {
+		if _, err := ReadNeighbourhoodPopulationCSV(strings.NewReader(invalid)); err == nil {
+			t.Errorf("ReadNeighbourhoodPopulationCSV(%q) should have returned an error for an invalid population", invalid)
+		}
+	}
+	if _, err := ReadNeighbourhoodPopulationCSV(strings.NewReader("a,1\n")); err == nil {
+		t.Errorf("ReadNeighbourhoodPopulationCSV should have returned an error for a short row")
+	}
+}
+
+func coord(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func TestNeighbourhoodRates(t *testing.T) {
+	boundary := func(x float64) []Location {
+		var ring []Location
+		for _, p := range square(x, 0, 1000) {
+			ring = append(ring, Location{
+				Latitude:  coord(p.Latitude),
+				Longitude: coord(p.Longitude),
+			})
+		}
+		return ring
+	}
+	location := func(x, y float64) Location {
+		p := offset(x, y)
+		return Location{Latitude: coord(p.Latitude), Longitude: coord(p.Longitude)}
+	}
+
+	crawl := &ForceCrawl{Force: "leicestershire", Neighbourhoods: []NeighbourhoodDetails{
+		{Neighbourhood: Neighbourhood{ID: "NC66", Population: "500"}, Boundary: boundary(1000)},
+		{Neighbourhood: Neighbourhood{ID: "NC04", Name: "City Centre", Population: "2,000"}, Boundary: boundary(0)},
+		{Neighbourhood: Neighbourhood{ID: "NC99"}, Boundary: boundary(2000)},
+	}}
+	crimes := []Crime{
+		{Category: "burglary", Location: location(500, 500)},
+		{Category: "burglary", Location: location(400, 500)},
+		{Category: "drugs", Location: location(600, 500)},
+		{Category: "drugs", Location: location(1500, 500)},
+		{Category: "drugs", Location: location(2500, 500)},
+		{Category: "drugs", Location: location(5000, 5000)},
+		{Category: "drugs"},
+	}
+	searches := []Search{{Location: location(500, 500)}, {Location: location(1500, 500)}, {Location: location(-500, 500)}}
+	overrides := NeighbourhoodPopulation{{Force: "leicestershire", Neighbourhood: "NC66"}: 1000}
+
+	report := NeighbourhoodRates(crawl, crimes, searches, overrides)
+	if report.UnassignedCrimes != 1 || report.UnassignedSearches != 1 {
+		t.Errorf("NeighbourhoodRates left %d crimes and %d searches unassigned, want 1 and 1",
+			report.UnassignedCrimes, report.UnassignedSearches)
+	}
+	if len(report.Neighbourhoods) != 3 {
+		t.Fatalf("NeighbourhoodRates returned %d neighbourhoods, want 3", len(report.Neighbourhoods))
+	}
+
+	nc04, nc66, nc99 := report.Neighbourhoods[0], report.Neighbourhoods[1], report.Neighbourhoods[2]
+	if nc04.Neighbourhood != "NC04" || nc04.Name != "City Centre" || nc04.PopulationSource != PopulationFromAPI {
+		t.Errorf("NeighbourhoodRates returned %v for NC04", nc04)
+	}
+	if nc04.Crimes != 3 || nc04.CrimeRate != 1.5 || nc04.SearchRate != 0.5 {
+		t.Errorf("NC04 has %d crimes at %v and searches at %v, want 3 at 1.5 and 0.5", nc04.Crimes, nc04.CrimeRate, nc04.SearchRate)
+	}
+	if got := nc04.CategoryRate("burglary"); got != 1 {
+		t.Errorf("NC04 burglary rate is %v, want 1", got)
+	}
+	if nc66.Population != 1000 || nc66.PopulationSource != PopulationFromOverride || nc66.CrimeRate != 1 {
+		t.Errorf("NeighbourhoodRates returned %v for NC66, want the overridden population", nc66)
+	}
+	if nc99.Crimes != 1 || nc99.PopulationSource != "" || nc99.CrimeRate != 0 || math.IsNaN(nc99.CrimeRate) {
+		t.Errorf("NeighbourhoodRates returned %v for NC99, want a count without a rate", nc99)
+	}
+}
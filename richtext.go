@@ -0,0 +1,565 @@
+package ukpolice
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HTMLToText converts an HTML fragment returned by the API into plain text.
+// Paragraphs are separated by blank lines, list items are prefixed with "- "
+// or their number and links are followed by their URL in parentheses.
+// Scripts, styles and embedded content are removed, as are links whose scheme
+// is not http, https, mailto or tel.
+func HTMLToText(s string) string {
+	return renderHTML(s, false)
+}
+
+// HTMLToMarkdown converts an HTML fragment returned by the API into Markdown.
+// Headings, emphasis, lists, quotes, code and links are kept and other markup
+// is removed as for HTMLToText. Characters with a meaning in Markdown are
+// escaped, including those that would start a heading or list at the start
+// of a line.
+func HTMLToMarkdown(s string) string {
+	return renderHTML(s, true)
+}
+
+// droppedElements are removed along with their content.
+var droppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "head": true, "title": true,
+	"form": true, "select": true, "textarea": true, "button": true,
+}
+
+// blockElements are separated from surrounding content by a blank line.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true,
+	"footer": true, "main": true, "aside": true, "nav": true, "address": true,
+	"table": true, "dl": true, "figure": true,
+}
+
+type htmlList struct {
+	ordered bool
+	n       int
+}
+
+type htmlLink struct {
+	href string
+	pos  int
+}
+
+// htmlRenderer accumulates the text of an HTML fragment.
+type htmlRenderer struct {
+	markdown bool
+	b        strings.Builder
+
+	breaks    int  // newlines to write before further content
+	space     bool // whether a space is pending
+	afterOpen bool // whether an opening marker was just written
+	lineStart bool // whether no text has been written on the current line
+	skip      int  // depth of dropped elements
+	pre       int  // depth of preformatted elements
+	quote     int  // depth of block quotes
+	cells     int  // cells written in the current table row
+	lists     []htmlList
+	links     []htmlLink
+	emphases  []string // open emphasis markers, innermost last
+}
+
+func renderHTML(s string, markdown bool) string {
+	r := &htmlRenderer{markdown: markdown}
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			r.text(s)
+			break
+		}
+		r.text(s[:i])
+		s = s[i:]
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return r.String()
+			}
+			s = s[end+3:]
+		case len(s) > 1 && (s[1] == '!' || s[1] == '?'):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return r.String()
+			}
+			s = s[end+1:]
+		case len(s) > 1 && (s[1] == '/' || isASCIILetter(s[1])):
+			end := tagEnd(s)
+			if end < 0 {
+				r.text(s)
+				return r.String()
+			}
+			name, closing, attrs := parseTag(s[1:end])
+			s = s[end+1:]
+			if !closing && (name == "script" || name == "style") {
+				// raw text elements may contain anything but their end tag.
+				close := strings.Index(strings.ToLower(s), "</"+name)
+				if close < 0 {
+					return r.String()
+				}
+				s = s[close:]
+				if end := strings.IndexByte(s, '>'); end >= 0 {
+					s = s[end+1:]
+				} else {
+					s = ""
+				}
+				continue
+			}
+			r.tag(name, closing, attrs)
+		default:
+			r.text("<")
+			s = s[1:]
+		}
+	}
+	return r.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// tagEnd returns the index of the '>' ending the tag at the start of s,
+// ignoring any within quoted attribute values, or -1.
+func tagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseTag parses the content of a tag between its angle brackets.
+func parseTag(s string) (string, bool, map[string]string) {
+	closing := strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+
+	i := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '/' })
+	if i < 0 {
+		return strings.ToLower(s), closing, nil
+	}
+	name := strings.ToLower(s[:i])
+	s = s[i:]
+
+	attrs := make(map[string]string)
+	for {
+		s = strings.TrimLeftFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '/' })
+		if s == "" {
+			break
+		}
+		end := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '=' })
+		if end < 0 {
+			attrs[strings.ToLower(s)] = ""
+			break
+		}
+		key := strings.ToLower(s[:end])
+		s = strings.TrimLeftFunc(s[end:], unicode.IsSpace)
+		if !strings.HasPrefix(s, "=") {
+			attrs[key] = ""
+			continue
+		}
+		s = strings.TrimLeftFunc(s[1:], unicode.IsSpace)
+
+		var value string
+		if s != "" && (s[0] == '"' || s[0] == '\'') {
+			q := s[0]
+			close := strings.IndexByte(s[1:], q)
+			if close < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:close+1], s[close+2:]
+			}
+		} else {
+			close := strings.IndexFunc(s, unicode.IsSpace)
+			if close < 0 {
+				value, s = s, ""
+			} else {
+				value, s = s[:close], s[close:]
+			}
+		}
+		attrs[key] = html.UnescapeString(value)
+	}
+	return name, closing, attrs
+}
+
+// safeURL reports whether a link to u may be kept.
+func safeURL(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	for _, scheme := range []string{"http://", "https://", "mailto:", "tel:"} {
+		if strings.HasPrefix(u, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownURL escapes the characters that would end a Markdown link
+// destination.
+var markdownURL = strings.NewReplacer(
+	"(", "%28", ")", "%29", " ", "%20", "<", "%3C", ">", "%3E", `"`, "%22", "\n", "%0A", "\r", "%0D", "\t", "%09",
+)
+
+// block ensures n newlines separate what follows from what came before.
+// Emphasis left open is closed at the end of a paragraph.
+func (r *htmlRenderer) block(n int) {
+	if n > 1 {
+		r.closeEmphasis(0)
+	}
+	if n > r.breaks {
+		r.breaks = n
+	}
+	r.space = false
+}
+
+// flush writes pending line breaks or a pending space.
+func (r *htmlRenderer) flush() {
+	if r.b.Len() == 0 {
+		r.breaks, r.space, r.lineStart = 0, false, true
+		if r.markdown && r.quote > 0 {
+			r.b.WriteString(strings.Repeat("> ", r.quote))
+		}
+		return
+	}
+	if r.breaks > 0 {
+		r.lineStart = true
+		prefix := ""
+		if r.markdown {
+			prefix = strings.Repeat("> ", r.quote)
+		}
+		for i := 0; i < r.breaks; i++ {
+			r.b.WriteString("\n")
+			if i < r.breaks-1 {
+				r.b.WriteString(strings.TrimRight(prefix, " "))
+			} else {
+				r.b.WriteString(prefix)
+			}
+		}
+	} else if r.space && !r.afterOpen {
+		r.b.WriteString(" ")
+	}
+	r.breaks, r.space, r.afterOpen = 0, false, false
+}
+
+// open writes an opening marker, such as "**", before further content.
+func (r *htmlRenderer) open(s string) {
+	r.flush()
+	r.b.WriteString(s)
+	r.afterOpen, r.lineStart = true, false
+}
+
+// marker writes a line marker, such as "- ", after which text is still at the
+// start of the line.
+func (r *htmlRenderer) marker(s string) {
+	r.open(s)
+	r.lineStart = true
+}
+
+// close writes a closing marker, keeping any pending space after it.
+func (r *htmlRenderer) close(s string) {
+	r.b.WriteString(s)
+	r.afterOpen = false
+}
+
+func (r *htmlRenderer) text(s string) {
+	if r.skip > 0 || s == "" {
+		return
+	}
+	s = strings.Replace(html.UnescapeString(s), "\u00a0", " ", -1)
+
+	if r.pre > 0 {
+		r.flush()
+		r.b.WriteString(s)
+		return
+	}
+
+	ordinal := -1 // index of the '.' or ')' of a number starting a line
+	for i, c := range s {
+		if unicode.IsSpace(c) {
+			r.space = true
+			continue
+		}
+		r.flush()
+		if r.markdown && r.lineStart {
+			if c >= '0' && c <= '9' {
+				j := i
+				for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+					j++
+				}
+				if j < len(s) && (s[j] == '.' || s[j] == ')') {
+					ordinal = j
+				}
+			} else if strings.ContainsRune("#-+=", c) {
+				r.b.WriteByte('\\')
+			}
+		}
+		if r.markdown && (strings.ContainsRune("\\`*_[]<>", c) || i == ordinal) {
+			r.b.WriteByte('\\')
+		}
+		r.b.WriteRune(c)
+		r.lineStart = false
+	}
+}
+
+func (r *htmlRenderer) tag(name string, closing bool, attrs map[string]string) {
+	if droppedElements[name] {
+		if closing {
+			if r.skip > 0 {
+				r.skip--
+			}
+		} else {
+			r.skip++
+		}
+		return
+	}
+	if r.skip > 0 {
+		return
+	}
+
+	switch {
+	case blockElements[name]:
+		r.block(2)
+	case name == "br":
+		r.block(1)
+	case name == "hr":
+		r.block(2)
+		if r.markdown {
+			r.open("---")
+			r.block(2)
+		}
+	case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+		r.block(2)
+		if r.markdown && !closing {
+			r.marker(strings.Repeat("#", int(name[1]-'0')) + " ")
+		}
+	case name == "strong" || name == "b":
+		r.emphasis("**", closing)
+	case name == "em" || name == "i":
+		r.emphasis("_", closing)
+	case name == "code" && r.pre == 0:
+		r.emphasis("`", closing)
+	case name == "pre":
+		r.block(2)
+		if closing {
+			if r.pre > 0 {
+				r.pre--
+			}
+			if r.markdown {
+				r.b.WriteString("\n```")
+			}
+			r.block(2)
+		} else {
+			if r.markdown {
+				r.open("```\n")
+			}
+			r.pre++
+		}
+	case name == "blockquote":
+		r.block(2)
+		if closing {
+			if r.quote > 0 {
+				r.quote--
+			}
+		} else {
+			r.quote++
+		}
+	case name == "ul" || name == "ol":
+		if closing {
+			if len(r.lists) > 0 {
+				r.lists = r.lists[:len(r.lists)-1]
+			}
+		} else {
+			r.lists = append(r.lists, htmlList{ordered: name == "ol"})
+		}
+		if len(r.lists) == 0 {
+			r.block(2)
+		} else {
+			r.block(1)
+		}
+	case name == "li":
+		r.block(1)
+		if closing {
+			return
+		}
+		marker := "- "
+		indent := ""
+		if n := len(r.lists); n > 0 {
+			l := &r.lists[n-1]
+			l.n++
+			if l.ordered {
+				marker = strconv.Itoa(l.n) + ". "
+			}
+			indent = strings.Repeat("  ", n-1)
+		}
+		r.marker(indent + marker)
+	case name == "tr":
+		r.block(1)
+		r.cells = 0
+	case name == "td" || name == "th":
+		if !closing {
+			if r.cells > 0 {
+				r.open(" | ")
+			}
+			r.cells++
+		}
+	case name == "a":
+		r.link(closing, attrs["href"])
+	}
+}
+
+func (r *htmlRenderer) emphasis(marker string, closing bool) {
+	if !r.markdown {
+		return
+	}
+	if !closing {
+		r.open(marker)
+		r.emphases = append(r.emphases, marker)
+		return
+	}
+	// close marker along with any emphasis opened inside it and not closed.
+	for i := len(r.emphases) - 1; i >= 0; i-- {
+		if r.emphases[i] == marker {
+			r.closeEmphasis(i)
+			return
+		}
+	}
+}
+
+// closeEmphasis closes the open emphasis markers from the ith onwards.
+func (r *htmlRenderer) closeEmphasis(i int) {
+	for len(r.emphases) > i {
+		n := len(r.emphases) - 1
+		r.close(r.emphases[n])
+		r.emphases = r.emphases[:n]
+	}
+}
+
+func (r *htmlRenderer) link(closing bool, href string) {
+	if !closing {
+		href = strings.TrimSpace(href)
+		if !safeURL(href) {
+			href = ""
+		}
+		if href != "" && r.markdown {
+			r.open("[")
+		}
+		r.links = append(r.links, htmlLink{href: href, pos: r.b.Len()})
+		return
+	}
+
+	if len(r.links) == 0 {
+		return
+	}
+	l := r.links[len(r.links)-1]
+	r.links = r.links[:len(r.links)-1]
+	if l.href == "" {
+		return
+	}
+	if r.markdown {
+		r.close("](" + markdownURL.Replace(l.href) + ")")
+		return
+	}
+
+	text := strings.TrimSpace(r.b.String()[l.pos:])
+	target := strings.TrimPrefix(strings.TrimPrefix(l.href, "mailto:"), "tel:")
+	if text == target || text == l.href {
+		return
+	}
+	if text == "" {
+		r.open(l.href)
+		r.afterOpen = false
+		return
+	}
+	r.close(" (" + l.href + ")")
+}
+
+// String returns the rendered text without surrounding blank lines or
+// trailing spaces.
+func (r *htmlRenderer) String() string {
+	r.closeEmphasis(0)
+	lines := strings.Split(r.b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// DescriptionText returns the force's description as plain text.
+func (f Force) DescriptionText() string {
+	return HTMLToText(f.Description)
+}
+
+// DescriptionMarkdown returns the force's description as Markdown.
+func (f Force) DescriptionMarkdown() string {
+	return HTMLToMarkdown(f.Description)
+}
+
+// BioText returns the officer's biography as plain text.
+func (so SeniorOfficer) BioText() string {
+	return HTMLToText(so.Bio)
+}
+
+// BioMarkdown returns the officer's biography as Markdown.
+func (so SeniorOfficer) BioMarkdown() string {
+	return HTMLToMarkdown(so.Bio)
+}
+
+// BioText returns the team member's biography as plain text.
+func (n NeighbourhoodTeam) BioText() string {
+	return HTMLToText(n.Bio)
+}
+
+// BioMarkdown returns the team member's biography as Markdown.
+func (n NeighbourhoodTeam) BioMarkdown() string {
+	return HTMLToMarkdown(n.Bio)
+}
+
+// DescriptionText returns the neighbourhood's description as plain text.
+func (n Neighbourhood) DescriptionText() string {
+	return HTMLToText(n.Description)
+}
+
+// DescriptionMarkdown returns the neighbourhood's description as Markdown.
+func (n Neighbourhood) DescriptionMarkdown() string {
+	return HTMLToMarkdown(n.Description)
+}
+
+// DescriptionText returns the event's description as plain text.
+func (n NeighbourhoodEvent) DescriptionText() string {
+	return HTMLToText(n.Description)
+}
+
+// IssueText returns the priority's issue as plain text.
+func (n NeighbourhoodPriorities) IssueText() string {
+	return HTMLToText(n.Issue)
+}
+
+// IssueMarkdown returns the priority's issue as Markdown.
+func (n NeighbourhoodPriorities) IssueMarkdown() string {
+	return HTMLToMarkdown(n.Issue)
+}
+
+// ActionText returns the action taken on the priority as plain text.
+func (n NeighbourhoodPriorities) ActionText() string {
+	return HTMLToText(n.Action)
+}
+
+// ActionMarkdown returns the action taken on the priority as Markdown.
+func (n NeighbourhoodPriorities) ActionMarkdown() string {
+	return HTMLToMarkdown(n.Action)
+}
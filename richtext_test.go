@@ -0,0 +1,115 @@
+package ukpolice
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Plain &amp; simple", "Plain & simple"},
+		{"<p>First   paragraph.</p><p>Second<br>line.</p>", "First paragraph.\n\nSecond\nline."},
+		{`<p>Visit <a href="http://www.leics.police.uk/">our website</a>.</p>`,
+			"Visit our website (http://www.leics.police.uk/)."},
+		{`Email <a href="mailto:a@example.police.uk">a@example.police.uk</a>`, "Email a@example.police.uk"},
+		{`<a href="javascript:alert(1)">click</a> here`, "click here"},
+		{"<ul><li>One</li><li>Two</li></ul><ol><li>First</li><li>Second</li></ol>",
+			"- One\n- Two\n\n1. First\n2. Second"},
+		{"<p>Safe</p><script>alert('<p>x</p>')</script><style>p{}</style><iframe src=x>frame</iframe>", "Safe"},
+		{"<p>It&#39;s&nbsp;<strong>important</strong></p><!-- note -->", "It's important"},
+		{"a < b", "a < b"},
+	}
+	for _, tt := range tests {
+		if got := HTMLToText(tt.in); got != tt.want {
+			t.Errorf("HTMLToText(%q) returned %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"<h2>Our team</h2><p>We are <strong>here </strong>to <em>help</em>.</p>",
+			"## Our team\n\nWe are **here** to _help_."},
+		{`<p>See <a href='https://example.police.uk/a_(b)'>the page</a></p>`,
+			"See [the page](https://example.police.uk/a_%28b%29)"},
+		{`<a href="data:text/html,x">bad</a>`, "bad"},
+		{"<ul><li>One<ul><li>Nested</li></ul></li><li>Two</li></ul>", "- One\n  - Nested\n- Two"},
+		{"<blockquote><p>Quoted</p><p>Twice</p></blockquote>After", "> Quoted\n>\n> Twice\n\nAfter"},
+		{"2 * 3_000 [x]", `2 \* 3\_000 \[x\]`},
+		{"<pre>line one\n  line two</pre>", "```\nline one\n  line two\n```"},
+	}
+	for _, tt := range tests {
+		if got := HTMLToMarkdown(tt.in); got != tt.want {
+			t.Errorf("HTMLToMarkdown(%q) returned %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToText_malformed(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"unclosed tag", "<p>Unclosed <strong>bold<p>next", "Unclosed bold\n\nnext"},
+		{"misnested tags", "<b><i>nested</b></i> after", "nested after"},
+		{"truncated tag", "text <p", "text <p"},
+		{"stray brackets", "<div><<p>>x</p>", "<\n\n>x"},
+		{"quoted bracket", `<p title="a>b">quoted &gt; bracket</p>`, "quoted > bracket"},
+		{"escaped markup", "&lt;script&gt;alert(1)&lt;/script&gt;", "<script>alert(1)</script>"},
+		{"entities", "&amp;amp; &#x41; &copy; &bogus; &", "&amp; A © &bogus; &"},
+		{"script with markup", "<SCRIPT>document.write('<b>x</b>')</SCRIPT>ok", "ok"},
+		{"script with similar end tag", "<script>a</scriptx>b</script>c", "bc"},
+		{"unclosed script", "before<script>never closed", "before"},
+		{"unclosed style", "before<style>p{}</style", "before"},
+		{"encoded javascript link", `<a href="javascript&#58;alert(1)">x</a>`, "x"},
+		{"spaced javascript link", `<a href=" JAVASCRIPT:alert(1)">x</a>`, "x"},
+		{"event handler", `<a href="http://x/" onclick="alert(1)">x</a>`, "x (http://x/)"},
+		{"unquoted link", `<a href=http://x/>unquoted</a>`, "unquoted (http://x/)"},
+	}
+	for _, tt := range tests {
+		if got := HTMLToText(tt.in); got != tt.want {
+			t.Errorf("%s: HTMLToText(%q) returned %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToMarkdown_malformed(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"unclosed emphasis", "<p>Unclosed <strong>bold<p>next", "Unclosed **bold**\n\nnext"},
+		{"misnested emphasis", "<b><i>nested</b></i> after", "**_nested_** after"},
+		{"unclosed emphasis at end", "<em>open", "_open_"},
+		{"escaped markup", "&lt;b&gt;x&lt;/b&gt;", `\<b\>x\</b\>`},
+		{"script", "<script>alert('*x*')</script>ok", "ok"},
+		{"link breaking out of destination", `<a href="https://x/a b)[y](javascript:alert(1)">x</a>`,
+			"[x](https://x/a%20b%29[y]%28javascript:alert%281%29)"},
+		{"link with title", `<a href='http://x/ "title"'>x</a>`, "[x](http://x/%20%22title%22)"},
+		{"heading", "<p># Not a heading</p>", `\# Not a heading`},
+		{"list", "<p>- not a list</p><p>+ nor this</p>", "\\- not a list\n\n\\+ nor this"},
+		{"ordered list", "<p>1. one</p><p>2) two</p><p>10 items</p>", "1\\. one\n\n2\\) two\n\n10 items"},
+		{"setext heading", "Line<br>===", "Line\n\\==="},
+		{"marker in list item", "<ul><li>- dash</li><li>1. one</li></ul>", "- \\- dash\n- 1\\. one"},
+		{"marker in heading", "<h1># hash</h1>", `# \# hash`},
+		{"markers mid-line", "a # b - c 1. d", "a # b - c 1. d"},
+	}
+	for _, tt := range tests {
+		if got := HTMLToMarkdown(tt.in); got != tt.want {
+			t.Errorf("%s: HTMLToMarkdown(%q) returned %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNeighbourhoodPriorities_Text(t *testing.T) {
+	p := NeighbourhoodPriorities{
+		Issue:  "<p>To reduce anti-social behaviour &amp; litter</p>",
+		Action: "<p>Patrols <b>increased</b></p>",
+	}
+	if got, want := p.IssueText(), "To reduce anti-social behaviour & litter"; got != want {
+		t.Errorf("NeighbourhoodPriorities.IssueText returned %q, want %q", got, want)
+	}
+	if got, want := p.ActionMarkdown(), "Patrols **increased**"; got != want {
+		t.Errorf("NeighbourhoodPriorities.ActionMarkdown returned %q, want %q", got, want)
+	}
+}
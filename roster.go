@@ -0,0 +1,323 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// RosterChangeType is the kind of change made to a team.
+type RosterChangeType string
+
+// Kinds of roster change.
+const (
+	RosterJoined         RosterChangeType = "joined"
+	RosterLeft           RosterChangeType = "left"
+	RosterRankChanged    RosterChangeType = "rank-changed"
+	RosterContactChanged RosterChangeType = "contact-changed"
+)
+
+// RosterMember is a member of a neighbourhood team or a senior officer of a
+// force.
+type RosterMember struct {
+	Name           string         `json:"name"`
+	Rank           string         `json:"rank,omitempty"`
+	Bio            string         `json:"bio,omitempty"`
+	ContactDetails ContactDetails `json:"contact_details,omitempty"`
+}
+
+func (m RosterMember) String() string {
+	return Stringify(m)
+}
+
+// copy returns m with its own map of contact details.
+func (m RosterMember) copy() RosterMember {
+	if m.ContactDetails != nil {
+		details := make(ContactDetails, len(m.ContactDetails))
+		for k, v := range m.ContactDetails {
+			details[k] = v
+		}
+		m.ContactDetails = details
+	}
+	return m
+}
+
+// RosterSnapshot holds the members of a team at a point in time. The senior
+// officers of a force are recorded with an empty Neighbourhood.
+type RosterSnapshot struct {
+	NeighbourhoodRef
+	Taken   time.Time      `json:"taken"`
+	Members []RosterMember `json:"members"`
+}
+
+func (r RosterSnapshot) String() string {
+	return Stringify(r)
+}
+
+func (r RosterSnapshot) ref() NeighbourhoodRef {
+	return r.NeighbourhoodRef
+}
+
+func (r RosterSnapshot) taken() time.Time {
+	return r.Taken
+}
+
+// copy returns r with its own copies of the members.
+func (r RosterSnapshot) copy() RosterSnapshot {
+	members := make([]RosterMember, len(r.Members))
+	for i, m := range r.Members {
+		members[i] = m.copy()
+	}
+	r.Members = members
+	return r
+}
+
+// RosterChange describes a change to one member of a team between two
+// snapshots. Previous is nil for joiners and Current is nil for leavers.
+type RosterChange struct {
+	Type RosterChangeType `json:"type"`
+	NeighbourhoodRef
+	Time     time.Time     `json:"time"`
+	Name     string        `json:"name"`
+	Previous *RosterMember `json:"previous,omitempty"`
+	Current  *RosterMember `json:"current,omitempty"`
+}
+
+func (r RosterChange) String() string {
+	return Stringify(r)
+}
+
+// RosterTracker stores snapshots of neighbourhood teams and senior officers
+// so that joiners, leavers, rank changes and contact changes can be reported.
+// It is safe for concurrent use.
+type RosterTracker struct {
+	store *snapshotStore
+}
+
+// NewRosterTracker returns an empty RosterTracker.
+func NewRosterTracker() *RosterTracker {
+	return &RosterTracker{store: newSnapshotStore()}
+}
+
+// RecordTeam stores a snapshot of a neighbourhood team taken at the provided
+// time and reports how the team differs from when it was last recorded. The
+// first time a team is recorded every member is reported as joining. Like
+// PriorityTracker, nothing is stored when the team is unchanged.
+func (r *RosterTracker) RecordTeam(force, neighbourhood string, taken time.Time, team []NeighbourhoodTeam) []RosterChange {
+	members := make([]RosterMember, len(team))
+	for i, m := range team {
+		members[i] = RosterMember{Name: m.Name, Rank: m.Rank, Bio: m.Bio, ContactDetails: m.ContactDetails}
+	}
+	return r.record(RosterSnapshot{
+		NeighbourhoodRef: NeighbourhoodRef{Force: force, Neighbourhood: neighbourhood},
+		Taken:            taken,
+		Members:          members,
+	})
+}
+
+// RecordPeople is RecordTeam for the senior officers of a force.
+func (r *RosterTracker) RecordPeople(force string, taken time.Time, officers []SeniorOfficer) []RosterChange {
+	members := make([]RosterMember, len(officers))
+	for i, m := range officers {
+		members[i] = RosterMember{Name: m.Name, Rank: m.Rank, Bio: m.Bio, ContactDetails: m.ContactDetails}
+	}
+	return r.record(RosterSnapshot{
+		NeighbourhoodRef: NeighbourhoodRef{Force: force},
+		Taken:            taken,
+		Members:          members,
+	})
+}
+
+// record stores a copy of current unless it matches the latest snapshot of
+// its team. A change of biography alone is stored but not reported.
+func (r *RosterTracker) record(current RosterSnapshot) []RosterChange {
+	current = current.copy()
+	var changes []RosterChange
+	r.store.record(current, func(previous snapshot) bool {
+		var p *RosterSnapshot
+		if previous != nil {
+			ps := previous.(RosterSnapshot)
+			p = &ps
+		}
+		changes = diffRoster(p, current)
+		return len(changes) > 0 || p == nil || !reflect.DeepEqual(p.Members, current.Members)
+	})
+	return changes
+}
+
+// RecordCrawl records the team of every neighbourhood in a force crawl whose
+// team was fetched, returning the changes.
+func (r *RosterTracker) RecordCrawl(crawl *ForceCrawl, taken time.Time) []RosterChange {
+	var changes []RosterChange
+	for _, d := range crawl.Neighbourhoods {
+		if _, failed := d.Errors[CrawlTeam]; failed {
+			continue
+		}
+		changes = append(changes, r.RecordTeam(crawl.Force, d.Neighbourhood.ID, taken, d.Team)...)
+	}
+	return changes
+}
+
+// PollTeam fetches the current team of a neighbourhood and records it.
+func (r *RosterTracker) PollTeam(ctx context.Context, n *NeighbourhoodService, force, neighbourhood string) ([]RosterChange, error) {
+	team, _, err := n.GetNeighbourhoodTeam(ctx, force, neighbourhood)
+	if err != nil {
+		return nil, err
+	}
+	return r.RecordTeam(force, neighbourhood, time.Now(), team), nil
+}
+
+// PollPeople fetches the current senior officers of a force and records them.
+func (r *RosterTracker) PollPeople(ctx context.Context, f *ForceService, force string) ([]RosterChange, error) {
+	officers, _, err := f.GetPeople(ctx, force)
+	if err != nil {
+		return nil, err
+	}
+	return r.RecordPeople(force, time.Now(), officers), nil
+}
+
+// Snapshots returns copies of the stored snapshots of a team, oldest first.
+// An empty neighbourhood selects the senior officers of the force.
+func (r *RosterTracker) Snapshots(force, neighbourhood string) []RosterSnapshot {
+	var snapshots []RosterSnapshot
+	for _, s := range r.store.snapshots(NeighbourhoodRef{Force: force, Neighbourhood: neighbourhood}) {
+		snapshots = append(snapshots, s.(RosterSnapshot).copy())
+	}
+	return snapshots
+}
+
+// Report returns every change to a team, oldest first. An empty neighbourhood
+// selects the senior officers of the force.
+func (r *RosterTracker) Report(force, neighbourhood string) []RosterChange {
+	var changes []RosterChange
+	var previous *RosterSnapshot
+	for _, s := range r.store.snapshots(NeighbourhoodRef{Force: force, Neighbourhood: neighbourhood}) {
+		current := s.(RosterSnapshot)
+		changes = append(changes, diffRoster(previous, current)...)
+		previous = &current
+	}
+	return changes
+}
+
+// ForceReport returns every change to the senior officers and neighbourhood
+// teams of a force ordered by time and neighbourhood.
+func (r *RosterTracker) ForceReport(force string) []RosterChange {
+	var changes []RosterChange
+	for _, ref := range r.store.refs(force) {
+		changes = append(changes, r.Report(ref.Force, ref.Neighbourhood)...)
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].Time.Equal(changes[j].Time) {
+			return changes[i].Time.Before(changes[j].Time)
+		}
+		return changes[i].Neighbourhood < changes[j].Neighbourhood
+	})
+	return changes
+}
+
+// diffRoster returns the changes from previous, which may be nil, to current.
+// Members are matched by name ignoring case, spacing and punctuation. A
+// member whose rank and contact details both changed produces two changes,
+// each with its own copies of the members.
+func diffRoster(previous *RosterSnapshot, current RosterSnapshot) []RosterChange {
+	change := func(t RosterChangeType, name string, p, c *RosterMember) RosterChange {
+		rc := RosterChange{
+			Type:             t,
+			NeighbourhoodRef: current.NeighbourhoodRef,
+			Time:             current.Taken,
+			Name:             name,
+		}
+		if p != nil {
+			previous := p.copy()
+			rc.Previous = &previous
+		}
+		if c != nil {
+			current := c.copy()
+			rc.Current = &current
+		}
+		return rc
+	}
+
+	var before []RosterMember
+	if previous != nil {
+		before = previous.Members
+	}
+	matched := make([]bool, len(before))
+
+	var changes []RosterChange
+	for i := range current.Members {
+		c := &current.Members[i]
+		j := -1
+		for k, b := range before {
+			if !matched[k] && squash(b.Name) == squash(c.Name) {
+				j = k
+				break
+			}
+		}
+		if j < 0 {
+			changes = append(changes, change(RosterJoined, c.Name, nil, c))
+			continue
+		}
+		matched[j] = true
+		p := &before[j]
+		if squash(p.Rank) != squash(c.Rank) {
+			changes = append(changes, change(RosterRankChanged, c.Name, p, c))
+		}
+		if !sameContactDetails(p.ContactDetails, c.ContactDetails) {
+			changes = append(changes, change(RosterContactChanged, c.Name, p, c))
+		}
+	}
+	for j := range before {
+		if !matched[j] {
+			changes = append(changes, change(RosterLeft, before[j].Name, &before[j], nil))
+		}
+	}
+	return changes
+}
+
+// sameContactDetails reports whether a and b hold the same non-empty
+// contact details.
+func sameContactDetails(a, b ContactDetails) bool {
+	return reflect.DeepEqual(a.Contacts(), b.Contacts())
+}
+
+// Encode writes the history of every team in the tracker to w as JSON.
+func (r *RosterTracker) Encode(w io.Writer) error {
+	var snapshots []RosterSnapshot
+	for _, s := range r.store.all() {
+		snapshots = append(snapshots, s.(RosterSnapshot))
+	}
+	return json.NewEncoder(w).Encode(snapshots)
+}
+
+// Decode merges the histories written by Encode into the tracker.
+func (r *RosterTracker) Decode(rd io.Reader) error {
+	var decoded []RosterSnapshot
+	if err := json.NewDecoder(rd).Decode(&decoded); err != nil {
+		return err
+	}
+	snapshots := make([]snapshot, len(decoded))
+	for i, s := range decoded {
+		snapshots[i] = s
+	}
+	r.store.add(snapshots)
+	return nil
+}
+
+// Save replaces the file at path with the output of Encode.
+func (r *RosterTracker) Save(path string) error {
+	return saveFile(path, r.Encode)
+}
+
+// LoadRosterTracker returns a tracker holding the histories in a file written
+// by Save.
+func LoadRosterTracker(path string) (*RosterTracker, error) {
+	r := NewRosterTracker()
+	if err := loadFile(path, r.Decode); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
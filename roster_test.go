@@ -0,0 +1,108 @@
+package ukpolice
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRosterTracker(t *testing.T) {
+	tracker := NewRosterTracker()
+
+	smith := NeighbourhoodTeam{Name: "Jane Smith", Rank: "Sergeant", ContactDetails: ContactDetails{"email": "j.smith@example.police.uk"}}
+	jones := NeighbourhoodTeam{Name: "Tom Jones", Rank: "PCSO"}
+	changes := tracker.RecordTeam("leicestershire", "NC04", day(1), []NeighbourhoodTeam{smith, jones})
+	if len(changes) != 2 || changes[0].Type != RosterJoined || changes[1].Type != RosterJoined {
+		t.Errorf("RosterTracker.RecordTeam returned %v, want two joiners", changes)
+	}
+
+	// a new biography alone is stored but not reported.
+	jonesBio := jones
+	jonesBio.Bio = "<p>Tom has served the city centre since 2010.</p>"
+	if changes := tracker.RecordTeam("leicestershire", "NC04", day(2), []NeighbourhoodTeam{smith, jonesBio}); len(changes) != 0 {
+		t.Errorf("RosterTracker.RecordTeam returned %v for a new biography", changes)
+	}
+
+	promoted := jonesBio
+	promoted.Name = "TOM JONES"
+	promoted.Rank = "PC"
+	promoted.ContactDetails = ContactDetails{"twitter": "https://twitter.com/PCTomJones"}
+	patel := NeighbourhoodTeam{Name: "Ravi Patel", Rank: "Sergeant"}
+	changes = tracker.RecordTeam("leicestershire", "NC04", day(3), []NeighbourhoodTeam{promoted, patel})
+
+	types := make([]RosterChangeType, len(changes))
+	for i, c := range changes {
+		types[i] = c.Type
+	}
+	want := []RosterChangeType{RosterRankChanged, RosterContactChanged, RosterJoined, RosterLeft}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("RosterTracker.RecordTeam returned changes %v, want %v", types, want)
+	}
+	if changes[0].Previous.Rank != "PCSO" || changes[0].Current.Rank != "PC" {
+		t.Errorf("rank change is %v", changes[0])
+	}
+	if changes[3].Name != "Jane Smith" || changes[3].Current != nil {
+		t.Errorf("leaver is %v", changes[3])
+	}
+
+	tracker.RecordPeople("leicestershire", day(2), []SeniorOfficer{{Name: "Simon Cole", Rank: "Chief Constable"}})
+
+	if got := len(tracker.Snapshots("leicestershire", "NC04")); got != 3 {
+		t.Errorf("RosterTracker.Snapshots returned %d snapshots, want 3", got)
+	}
+	if got := len(tracker.Report("leicestershire", "NC04")); got != 6 {
+		t.Errorf("RosterTracker.Report returned %d changes, want 6", got)
+	}
+
+	var buf bytes.Buffer
+	if err := tracker.Encode(&buf); err != nil {
+		t.Fatalf("RosterTracker.Encode returned error: '%s'", err)
+	}
+	decoded := NewRosterTracker()
+	if err := decoded.Decode(&buf); err != nil {
+		t.Fatalf("RosterTracker.Decode returned error: '%s'", err)
+	}
+	report := decoded.ForceReport("leicestershire")
+	if len(report) != 7 {
+		t.Fatalf("RosterTracker.ForceReport returned %d changes, want 7", len(report))
+	}
+	if c := report[2]; c.Type != RosterJoined || c.Name != "Simon Cole" || c.Neighbourhood != "" {
+		t.Errorf("RosterTracker.ForceReport()[2] is %v, want the senior officer joining", c)
+	}
+
+	// neither the team passed in nor the results share memory with the
+	// tracker.
+	smith.ContactDetails["email"] = "changed"
+	changes[1].Current.ContactDetails["twitter"] = "changed"
+	tracker.Snapshots("leicestershire", "NC04")[0].Members[0].ContactDetails["email"] = "changed"
+	snapshots := tracker.Snapshots("leicestershire", "NC04")
+	if snapshots[0].Members[0].ContactDetails["email"] != "j.smith@example.police.uk" ||
+		snapshots[2].Members[0].ContactDetails["twitter"] != "https://twitter.com/PCTomJones" {
+		t.Errorf("RosterTracker results share memory with its snapshots: %v", snapshots)
+	}
+}
+
+func TestRosterTracker_Save(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "roster.json")
+
+	tracker := NewRosterTracker()
+	tracker.RecordPeople("leicestershire", day(1), []SeniorOfficer{{Name: "Simon Cole", Rank: "Chief Constable"}})
+	if err := tracker.Save(path); err != nil {
+		t.Fatalf("RosterTracker.Save returned error: '%s'", err)
+	}
+	loaded, err := LoadRosterTracker(path)
+	if err != nil {
+		t.Fatalf("LoadRosterTracker returned error: '%s'", err)
+	}
+	if got, want := loaded.Snapshots("leicestershire", ""), tracker.Snapshots("leicestershire", ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadRosterTracker returned snapshots %v, want %v", got, want)
+	}
+}
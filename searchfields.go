@@ -0,0 +1,376 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// AgeRange is the age range of the person searched.
+type AgeRange string
+
+// Age ranges used by the API.
+const (
+	AgeUnder10 AgeRange = "under 10"
+	Age10To17  AgeRange = "10-17"
+	Age18To24  AgeRange = "18-24"
+	Age25To34  AgeRange = "25-34"
+	AgeOver34  AgeRange = "over 34"
+)
+
+// Gender is the gender of the person searched.
+type Gender string
+
+// Genders used by the API.
+const (
+	GenderMale   Gender = "Male"
+	GenderFemale Gender = "Female"
+	GenderOther  Gender = "Other"
+)
+
+// SearchType is the type of stop and search.
+type SearchType string
+
+// Search types used by the API.
+const (
+	PersonSearch           SearchType = "Person search"
+	VehicleSearch          SearchType = "Vehicle search"
+	PersonAndVehicleSearch SearchType = "Person and Vehicle search"
+)
+
+// EthnicGroup is one of the five high-level ethnic groups used by the Office
+// for National Statistics. Officer-defined ethnicity is recorded using these
+// groups.
+type EthnicGroup string
+
+// ONS ethnic groups.
+const (
+	EthnicGroupWhite EthnicGroup = "White"
+	EthnicGroupMixed EthnicGroup = "Mixed"
+	EthnicGroupAsian EthnicGroup = "Asian"
+	EthnicGroupBlack EthnicGroup = "Black"
+	EthnicGroupOther EthnicGroup = "Other"
+)
+
+// EthnicGroups holds every ONS ethnic group in the order used by the ONS.
+var EthnicGroups = []EthnicGroup{
+	EthnicGroupWhite, EthnicGroupMixed, EthnicGroupAsian, EthnicGroupBlack, EthnicGroupOther,
+}
+
+// SelfDefinedEthnicity is the ethnicity of the person searched as they
+// described it, e.g. "White - English/Welsh/Scottish/Northern Irish/British".
+type SelfDefinedEthnicity string
+
+// Legislation is the power under which a stop and search was conducted.
+type Legislation string
+
+// Common legislation used by the API.
+const (
+	MisuseOfDrugsAct                 Legislation = "Misuse of Drugs Act 1971 (section 23)"
+	PoliceAndCriminalEvidenceAct     Legislation = "Police and Criminal Evidence Act 1984 (section 1)"
+	CriminalJusticeAndPublicOrderAct Legislation = "Criminal Justice and Public Order Act 1994 (section 60)"
+	FirearmsAct                      Legislation = "Firearms Act 1968 (section 47)"
+	TerrorismAct                     Legislation = "Terrorism Act 2000 (section 47A)"
+	PsychoactiveSubstancesAct        Legislation = "Psychoactive Substances Act 2016 (s36(2))"
+	CriminalJusticeAct               Legislation = "Criminal Justice Act 1988 (section 139B)"
+)
+
+// ObjectOfSearch is what the officer was searching for.
+type ObjectOfSearch string
+
+// Common objects of search used by the API.
+const (
+	ControlledDrugs            ObjectOfSearch = "Controlled drugs"
+	OffensiveWeapons           ObjectOfSearch = "Offensive weapons"
+	StolenGoods                ObjectOfSearch = "Stolen goods"
+	ArticleForUseInTheft       ObjectOfSearch = "Article for use in theft"
+	ArticlesForCriminalDamage  ObjectOfSearch = "Articles for use in criminal damage"
+	Firearms                   ObjectOfSearch = "Firearms"
+	AnythingToThreatenOrHarm   ObjectOfSearch = "Anything to threaten or harm anyone"
+	EvidenceOfOffences         ObjectOfSearch = "Evidence of offences under the Act"
+	Fireworks                  ObjectOfSearch = "Fireworks"
+	PsychoactiveSubstances     ObjectOfSearch = "Psychoactive substances"
+	Crossbows                  ObjectOfSearch = "Crossbows"
+	EvidenceOfWildlifeOffences ObjectOfSearch = "Evidence of wildlife offences"
+	GoodsOnWhichDutyNotPaid    ObjectOfSearch = "Goods on which duty has not been paid etc."
+	GameOrPoachingEquipment    ObjectOfSearch = "Game or poaching equipment"
+	SealsOrHuntingEquipment    ObjectOfSearch = "Seals or hunting equipment"
+	ObjectOfSearchUnavailable  ObjectOfSearch = "Detailed object of search unavailable"
+)
+
+// enumTable maps variants of known values onto their canonical spelling.
+type enumTable map[string]string
+
+// newEnumTable returns a table recognising values and the provided variants,
+// which map a variant onto a canonical value.
+func newEnumTable(values []string, variants map[string]string) enumTable {
+	t := make(enumTable)
+	for _, v := range values {
+		t[enumKey(v)] = v
+	}
+	for variant, v := range variants {
+		t[enumKey(variant)] = v
+	}
+	return t
+}
+
+// enumKey reduces s to lower case letters, digits and plus signs, abbreviating
+// "section" and "sec." to "s" so that references to legislation compare equal.
+func enumKey(s string) string {
+	s = strings.ToLower(s)
+	s = strings.Replace(s, "section", "s", -1)
+	s = strings.Replace(s, "sec.", "s", -1)
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// normalise returns the canonical spelling of s, or s with surrounding and
+// repeated whitespace removed if it is unknown.
+func (t enumTable) normalise(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if v, ok := t[enumKey(s)]; ok {
+		return v
+	}
+	return s
+}
+
+// unmarshal decodes a JSON string, treating null as empty, and normalises it.
+func (t enumTable) unmarshal(b []byte) (string, error) {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return "", err
+	}
+	if s == nil {
+		return "", nil
+	}
+	return t.normalise(*s), nil
+}
+
+var (
+	ageRanges = newEnumTable(
+		[]string{string(AgeUnder10), string(Age10To17), string(Age18To24), string(Age25To34), string(AgeOver34)},
+		map[string]string{"34+": string(AgeOver34), "over34": string(AgeOver34), "<10": string(AgeUnder10)},
+	)
+	genders = newEnumTable(
+		[]string{string(GenderMale), string(GenderFemale), string(GenderOther)},
+		map[string]string{"m": string(GenderMale), "f": string(GenderFemale)},
+	)
+	searchTypes = newEnumTable(
+		[]string{string(PersonSearch), string(VehicleSearch), string(PersonAndVehicleSearch)},
+		map[string]string{"person & vehicle search": string(PersonAndVehicleSearch)},
+	)
+	legislation = newEnumTable(
+		[]string{string(MisuseOfDrugsAct), string(PoliceAndCriminalEvidenceAct),
+			string(CriminalJusticeAndPublicOrderAct), string(FirearmsAct), string(TerrorismAct),
+			string(PsychoactiveSubstancesAct), string(CriminalJusticeAct),
+			"Police and Criminal Evidence Act 1984 (section 6)",
+			"Criminal Justice and Public Order Act 1994 (section 60AA)",
+			"Terrorism Act 2000 (section 43)",
+			"Terrorism Act 2000 (section 43A)",
+			"Environmental Protection Act 1990 (section 34B)",
+			"Poaching Prevention Act 1862 (section 2)",
+			"Wildlife and Countryside Act 1981 (section 19)",
+			"Customs and Excise Management Act 1979 (section 163)",
+			"Sporting events (Control of Alcohol etc.) Act 1985 (section 7)",
+			"Aviation Security Act 1982 (section 27(1))",
+			"Crossbows Act 1987 (section 4)",
+			"Deer Act 1991 (section 12)",
+			"Protection of Badgers Act 1992 (section 11)",
+			"Conservation of Seals Act 1970 (section 4)",
+			"Hunting Act 2004 (section 8)",
+			"Public Stores Act 1875 (section 6)"},
+		nil,
+	)
+	objectsOfSearch = newEnumTable(
+		[]string{string(ControlledDrugs), string(OffensiveWeapons), string(StolenGoods), string(ArticleForUseInTheft),
+			string(ArticlesForCriminalDamage), string(Firearms), string(AnythingToThreatenOrHarm),
+			string(EvidenceOfOffences), string(Fireworks), string(PsychoactiveSubstances), string(Crossbows),
+			string(EvidenceOfWildlifeOffences), string(GoodsOnWhichDutyNotPaid), string(GameOrPoachingEquipment),
+			string(SealsOrHuntingEquipment), string(ObjectOfSearchUnavailable)},
+		map[string]string{
+			"Articles for use in theft":          string(ArticleForUseInTheft),
+			"Article for use in criminal damage": string(ArticlesForCriminalDamage),
+			"Offensive weapon":                   string(OffensiveWeapons),
+			"Firearm":                            string(Firearms),
+			"Controlled drug":                    string(ControlledDrugs),
+		},
+	)
+)
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *AgeRange) UnmarshalJSON(b []byte) error {
+	s, err := ageRanges.unmarshal(b)
+	*a = AgeRange(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (g *Gender) UnmarshalJSON(b []byte) error {
+	s, err := genders.unmarshal(b)
+	*g = Gender(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *SearchType) UnmarshalJSON(b []byte) error {
+	s, err := searchTypes.unmarshal(b)
+	*t = SearchType(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Groups are parsed
+// with ParseEthnicGroup so that the longer ONS names are accepted, and values
+// it does not recognise are kept.
+func (e *EthnicGroup) UnmarshalJSON(b []byte) error {
+	s, err := enumTable(nil).unmarshal(b)
+	if group := ParseEthnicGroup(s); group != "" {
+		*e = group
+		return err
+	}
+	*e = EthnicGroup(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Only whitespace is
+// normalised as self-defined ethnicities are recorded in many forms.
+func (e *SelfDefinedEthnicity) UnmarshalJSON(b []byte) error {
+	s, err := enumTable(nil).unmarshal(b)
+	*e = SelfDefinedEthnicity(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *Legislation) UnmarshalJSON(b []byte) error {
+	s, err := legislation.unmarshal(b)
+	*l = Legislation(s)
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (o *ObjectOfSearch) UnmarshalJSON(b []byte) error {
+	s, err := objectsOfSearch.unmarshal(b)
+	*o = ObjectOfSearch(s)
+	return err
+}
+
+// ethnicGroupNames maps the keys of the names of ONS ethnic groups to the
+// group.
+var ethnicGroupNames = map[string]EthnicGroup{
+	"white":                             EthnicGroupWhite,
+	"mixed":                             EthnicGroupMixed,
+	"mixedmultiple":                     EthnicGroupMixed,
+	"mixedmultipleethnicgroup":          EthnicGroupMixed,
+	"mixedmultipleethnicgroups":         EthnicGroupMixed,
+	"asian":                             EthnicGroupAsian,
+	"asianasianbritish":                 EthnicGroupAsian,
+	"asianorasianbritish":               EthnicGroupAsian,
+	"black":                             EthnicGroupBlack,
+	"blackblackbritish":                 EthnicGroupBlack,
+	"blackorblackbritish":               EthnicGroupBlack,
+	"blackafricancaribbeanblackbritish": EthnicGroupBlack,
+	"other":                             EthnicGroupOther,
+	"otherethnicgroup":                  EthnicGroupOther,
+	"otherethnicgroups":                 EthnicGroupOther,
+}
+
+// ethnicSubgroups maps the keys of the names of the 2011 census ethnic
+// groups within each ONS group, and common short forms of them, to the ONS
+// group.
+var ethnicSubgroups = map[string]EthnicGroup{
+	"englishwelshscottishnorthernirishbritish": EthnicGroupWhite,
+	"british":                    EthnicGroupWhite,
+	"whitebritish":               EthnicGroupWhite,
+	"irish":                      EthnicGroupWhite,
+	"whiteirish":                 EthnicGroupWhite,
+	"gypsyoririshtraveller":      EthnicGroupWhite,
+	"whitegypsyoririshtraveller": EthnicGroupWhite,
+	"anyotherwhitebackground":    EthnicGroupWhite,
+	"otherwhite":                 EthnicGroupWhite,
+	"whiteother":                 EthnicGroupWhite,
+
+	"whiteandblackcaribbean":                EthnicGroupMixed,
+	"whiteandblackafrican":                  EthnicGroupMixed,
+	"whiteandasian":                         EthnicGroupMixed,
+	"anyothermixedmultipleethnicbackground": EthnicGroupMixed,
+	"anyothermixedbackground":               EthnicGroupMixed,
+	"othermixed":                            EthnicGroupMixed,
+	"mixedother":                            EthnicGroupMixed,
+
+	"indian":                  EthnicGroupAsian,
+	"pakistani":               EthnicGroupAsian,
+	"bangladeshi":             EthnicGroupAsian,
+	"chinese":                 EthnicGroupAsian,
+	"anyotherasianbackground": EthnicGroupAsian,
+	"otherasian":              EthnicGroupAsian,
+	"asianother":              EthnicGroupAsian,
+
+	"african":        EthnicGroupBlack,
+	"blackafrican":   EthnicGroupBlack,
+	"caribbean":      EthnicGroupBlack,
+	"blackcaribbean": EthnicGroupBlack,
+	"anyotherblackafricancaribbeanbackground": EthnicGroupBlack,
+	"anyotherblackbackground":                 EthnicGroupBlack,
+	"otherblack":                              EthnicGroupBlack,
+	"blackother":                              EthnicGroupBlack,
+
+	"arab":                EthnicGroupOther,
+	"anyotherethnicgroup": EthnicGroupOther,
+}
+
+// ParseEthnicGroup returns the ONS ethnic group named by s, ignoring case and
+// punctuation. It accepts the longer ONS names of the groups, such as
+// "Black/African/Caribbean/Black British", the names of the 2011 census
+// groups within them, such as "White and Black Caribbean", and the two
+// together, such as "Asian/Asian British: Chinese". An empty group is
+// returned if s is not recognised.
+func ParseEthnicGroup(s string) EthnicGroup {
+	key := enumKey(s)
+	if group, ok := ethnicGroupNames[key]; ok {
+		return group
+	}
+	if group, ok := ethnicSubgroups[key]; ok {
+		return group
+	}
+	for _, sep := range []string{":", " - "} {
+		if i := strings.Index(s, sep); i >= 0 {
+			if group := ParseEthnicGroup(s[:i]); group != "" {
+				return group
+			}
+			return ParseEthnicGroup(s[i+len(sep):])
+		}
+	}
+	return ""
+}
+
+// Group returns the ONS ethnic group of a self-defined ethnicity using either
+// its 16+1 census code, e.g. "(W1)", or its leading group name. An empty group
+// is returned if the ethnicity was not stated or is not recognised.
+func (e SelfDefinedEthnicity) Group() EthnicGroup {
+	s := string(e)
+	if strings.Contains(strings.ToLower(s), "not stated") {
+		return ""
+	}
+
+	if i := strings.LastIndex(s, "("); i >= 0 && strings.HasSuffix(s, ")") && len(s)-i > 2 {
+		switch s[i+1] {
+		case 'W':
+			return EthnicGroupWhite
+		case 'M':
+			return EthnicGroupMixed
+		case 'A':
+			return EthnicGroupAsian
+		case 'B':
+			return EthnicGroupBlack
+		case 'O':
+			return EthnicGroupOther
+		case 'N':
+			return ""
+		}
+	}
+
+	return ParseEthnicGroup(s)
+}
@@ -0,0 +1,170 @@
+package ukpolice
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSearch_UnmarshalNormalisesFields(t *testing.T) {
+	raw := `{
+		"age_range": "Over 34",
+		"gender": " male ",
+		"type": "Person & Vehicle search",
+		"officer_defined_ethnicity": "black",
+		"self_defined_ethnicity": "White -  White British (W1)",
+		"legislation": "Environmental Protection Act 1990 (section 34B )",
+		"object_of_search": "Articles for use in theft"
+	}`
+
+	var s Search
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+
+	tt := []struct {
+		name      string
+		got, want string
+	}{
+		{"AgeRange", string(s.AgeRange), string(AgeOver34)},
+		{"Gender", string(s.Gender), string(GenderMale)},
+		{"Type", string(s.Type), string(PersonAndVehicleSearch)},
+		{"OfficerDefinedEthnicity", string(s.OfficerDefinedEthnicity), string(EthnicGroupBlack)},
+		{"SelfDefinedEthnicity", string(s.SelfDefinedEthnicity), "White - White British (W1)"},
+		{"Legislation", string(s.Legislation), "Environmental Protection Act 1990 (section 34B)"},
+		{"ObjectOfSearch", string(s.ObjectOfSearch), string(ArticleForUseInTheft)},
+	}
+	for _, tc := range tt {
+		if tc.got != tc.want {
+			t.Errorf("%s unmarshalled as %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestSearch_UnmarshalKeepsUnknownValues(t *testing.T) {
+	raw := `{
+		"age_range": null,
+		"gender": "Non-binary",
+		"legislation": "Some  New Act 2030 (section 1)",
+		"object_of_search": "Something else"
+	}`
+
+	var s Search
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+	if s.AgeRange != "" || s.Gender != "Non-binary" || s.Legislation != "Some New Act 2030 (section 1)" ||
+		s.ObjectOfSearch != "Something else" {
+		t.Errorf("unknown values were not kept: %v", s)
+	}
+}
+
+func TestLegislationVariants(t *testing.T) {
+	for _, variant := range []string{
+		"Misuse of Drugs Act 1971 (section 23)",
+		"misuse of drugs act 1971 (s.23)",
+		"Misuse of Drugs Act 1971 (sec. 23)",
+		"Misuse of Drugs Act 1971 s23",
+	} {
+		if got := legislation.normalise(variant); got != string(MisuseOfDrugsAct) {
+			t.Errorf("normalise(%q) returned %q, want %q", variant, got, MisuseOfDrugsAct)
+		}
+	}
+	if got := legislation.normalise("Psychoactive Substances Act 2016 (section 36(2))"); got != string(PsychoactiveSubstancesAct) {
+		t.Errorf("normalise returned %q, want %q", got, PsychoactiveSubstancesAct)
+	}
+}
+
+func TestSelfDefinedEthnicity_Group(t *testing.T) {
+	tt := []struct {
+		in   SelfDefinedEthnicity
+		want EthnicGroup
+	}{
+		{"White - White British (W1)", EthnicGroupWhite},
+		{"White - English/Welsh/Scottish/Northern Irish/British", EthnicGroupWhite},
+		{"Mixed/Multiple ethnic groups - White and Black Caribbean", EthnicGroupMixed},
+		{"Asian/Asian British - Indian", EthnicGroupAsian},
+		{"Black/African/Caribbean/Black British - African", EthnicGroupBlack},
+		{"Black - Any other Black background (B9)", EthnicGroupBlack},
+		{"Other ethnic group - Any other ethnic group", EthnicGroupOther},
+		{"Other ethnic group - Not stated", ""},
+		{"Not Stated (NS)", ""},
+		{"", ""},
+	}
+	for _, tc := range tt {
+		if got := tc.in.Group(); got != tc.want {
+			t.Errorf("Group(%q) returned %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEthnicGroup_UnmarshalJSON(t *testing.T) {
+	tt := []struct {
+		in   string
+		want EthnicGroup
+	}{
+		{`"White"`, EthnicGroupWhite},
+		{`"Black or Black British"`, EthnicGroupBlack},
+		{`"Asian or Asian British"`, EthnicGroupAsian},
+		{`"Mixed/Multiple ethnic groups"`, EthnicGroupMixed},
+		{`"Other ethnic group"`, EthnicGroupOther},
+		{`"Not  stated"`, "Not stated"},
+		{`null`, ""},
+	}
+	for _, tc := range tt {
+		var got EthnicGroup
+		if err := json.Unmarshal([]byte(tc.in), &got); err != nil {
+			t.Errorf("json.Unmarshal(%s) returned error: %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Errorf("json.Unmarshal(%s) returned %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseEthnicGroup(t *testing.T) {
+	tt := []struct {
+		in   string
+		want EthnicGroup
+	}{
+		{"white", EthnicGroupWhite},
+		{"Black/African/Caribbean/Black British", EthnicGroupBlack},
+		{"Mixed/Multiple ethnic groups", EthnicGroupMixed},
+		{"Asian/Asian British", EthnicGroupAsian},
+		{"Other ethnic group", EthnicGroupOther},
+		{"Unknown", ""},
+		{"Whitehall", ""},
+		{"Other", EthnicGroupOther},
+
+		// 2011 census groups.
+		{"English/Welsh/Scottish/Northern Irish/British", EthnicGroupWhite},
+		{"Irish", EthnicGroupWhite},
+		{"Gypsy or Irish Traveller", EthnicGroupWhite},
+		{"Any other White background", EthnicGroupWhite},
+		{"White and Black Caribbean", EthnicGroupMixed},
+		{"White and Black African", EthnicGroupMixed},
+		{"White and Asian", EthnicGroupMixed},
+		{"Any other Mixed/Multiple ethnic background", EthnicGroupMixed},
+		{"Indian", EthnicGroupAsian},
+		{"Pakistani", EthnicGroupAsian},
+		{"Bangladeshi", EthnicGroupAsian},
+		{"Chinese", EthnicGroupAsian},
+		{"Any other Asian background", EthnicGroupAsian},
+		{"African", EthnicGroupBlack},
+		{"Caribbean", EthnicGroupBlack},
+		{"Any other Black/African/Caribbean background", EthnicGroupBlack},
+		{"Arab", EthnicGroupOther},
+		{"Any other ethnic group", EthnicGroupOther},
+
+		// census groups with their ONS group.
+		{"White: Irish", EthnicGroupWhite},
+		{"Mixed/multiple ethnic groups: White and Black Caribbean", EthnicGroupMixed},
+		{"Asian/Asian British: Chinese", EthnicGroupAsian},
+		{"Black/African/Caribbean/Black British - Caribbean", EthnicGroupBlack},
+		{"Other ethnic group: Arab", EthnicGroupOther},
+	}
+	for _, tc := range tt {
+		if got := ParseEthnicGroup(tc.in); got != tc.want {
+			t.Errorf("ParseEthnicGroup(%q) returned %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ukpolice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"time"
+)
+
+// londonLocation is the time zone in which the police record local times.
+var londonLocation = loadLondon()
+
+// loadLondon loads the Europe/London time zone from the system time zone
+// database, or builds it with londonTZData on systems without one.
+func loadLondon() *time.Location {
+	if loc, err := time.LoadLocation("Europe/London"); err == nil {
+		return loc
+	}
+	loc, err := time.LoadLocationFromTZData("Europe/London", londonTZData())
+	if err != nil {
+		panic("ukpolice: invalid Europe/London zone data: " + err.Error())
+	}
+	return loc
+}
+
+// londonTZData returns the Europe/London time zone in the TZif format of
+// RFC 8536. It holds the rules in force since 1996, under which British
+// Summer Time runs from 01:00 UTC on the last Sunday of March to 01:00 UTC
+// on the last Sunday of October, for the years up to 2037.
+func londonTZData() []byte {
+	lastSunday := func(year int, month time.Month) time.Time {
+		t := time.Date(year, month+1, 1, 1, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		return t.AddDate(0, 0, -int(t.Weekday()))
+	}
+	var times []int32
+	var types []byte
+	for year := 1996; year <= 2037; year++ {
+		times = append(times, int32(lastSunday(year, time.March).Unix()), int32(lastSunday(year, time.October).Unix()))
+		types = append(types, 1, 0)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("TZif")
+	buf.Write(make([]byte, 16)) // version 1 and reserved bytes.
+	// counts of UT/local indicators, standard/wall indicators, leap seconds,
+	// transitions, local time types and abbreviation bytes.
+	for _, n := range []int{0, 0, 0, len(times), 2, 8} {
+		binary.Write(&buf, binary.BigEndian, uint32(n))
+	}
+	binary.Write(&buf, binary.BigEndian, times)
+	buf.Write(types)
+	// GMT at UTC and BST an hour ahead.
+	buf.Write([]byte{0, 0, 0, 0, 0, 0})
+	buf.Write([]byte{0, 0, 0x0e, 0x10, 1, 4})
+	buf.WriteString("GMT\x00BST\x00")
+	return buf.Bytes()
+}
+
+// SearchFilter reports whether a search should be included in an analysis.
+type SearchFilter func(Search) bool
+
+// LegislationFilter matches searches conducted under any of the provided
+// legislation.
+func LegislationFilter(legislation ...Legislation) SearchFilter {
+	return func(s Search) bool {
+		for _, l := range legislation {
+			if s.Legislation == l {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OutcomeFilter matches searches whose outcome ID, name or description is any
+// of the provided outcomes.
+func OutcomeFilter(outcomes ...string) SearchFilter {
+	return func(s Search) bool {
+		for _, o := range outcomes {
+			if s.Outcome.ID == o || s.Outcome.Name == o || s.Outcome.Desc == o {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AgeRangeFilter matches searches of people in any of the provided age ranges.
+func AgeRangeFilter(ranges ...AgeRange) SearchFilter {
+	return func(s Search) bool {
+		for _, a := range ranges {
+			if s.AgeRange == a {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterSearches returns the searches matching every filter.
+func FilterSearches(searches []Search, filters ...SearchFilter) []Search {
+	var matched []Search
+	for _, s := range searches {
+		if matches(s, filters) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+func matches(s Search, filters []SearchFilter) bool {
+	for _, f := range filters {
+		if !f(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// HourWeekdayMatrix counts searches by day of the week, indexed by
+// time.Weekday, and hour of the day.
+type HourWeekdayMatrix [7][24]int
+
+// OperationSummary holds the searches conducted as part of a named operation.
+type OperationSummary struct {
+	Name     string    `json:"name"`
+	Searches int       `json:"searches"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+	// ByLegislation counts the operation's searches by legislation.
+	ByLegislation map[Legislation]int `json:"by_legislation"`
+}
+
+func (o OperationSummary) String() string {
+	return Stringify(o)
+}
+
+// TemporalPatterns holds when searches took place in Europe/London local time.
+type TemporalPatterns struct {
+	Searches int `json:"searches"`
+	// HourWeekday is suitable for a day by hour heatmap.
+	HourWeekday HourWeekdayMatrix `json:"hour_weekday"`
+	// ByMonth counts searches in each month, in the format YYYY-MM, giving
+	// the trend over time.
+	ByMonth map[string]int `json:"by_month"`
+	// ByMonthOfYear counts searches by calendar month, indexed from January,
+	// giving the seasonal pattern.
+	ByMonthOfYear [12]int `json:"by_month_of_year"`
+	// Operations holds the searches in each named operation ordered by the
+	// time of the first search.
+	Operations []OperationSummary `json:"operations"`
+}
+
+func (t TemporalPatterns) String() string {
+	return Stringify(t)
+}
+
+// Months returns the months in ByMonth in ascending order.
+func (t *TemporalPatterns) Months() []string {
+	months := make([]string, 0, len(t.ByMonth))
+	for m := range t.ByMonth {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	return months
+}
+
+// SearchPatterns analyses when the searches matching every filter took place.
+// Times are converted to Europe/London local time, so the hour of a search
+// reflects British Summer Time where it applied. Searches without a time are
+// ignored.
+func SearchPatterns(searches []Search, filters ...SearchFilter) *TemporalPatterns {
+	p := &TemporalPatterns{ByMonth: make(map[string]int)}
+	operations := make(map[string]*OperationSummary)
+	for _, s := range searches {
+		if s.DateTime.IsZero() || !matches(s, filters) {
+			continue
+		}
+
+		local := s.DateTime.In(londonLocation)
+		p.Searches++
+		p.HourWeekday[local.Weekday()][local.Hour()]++
+		p.ByMonth[local.Format(monthLayout)]++
+		p.ByMonthOfYear[local.Month()-1]++
+
+		if s.OperationName == "" {
+			continue
+		}
+		op, ok := operations[s.OperationName]
+		if !ok {
+			op = &OperationSummary{
+				Name:          s.OperationName,
+				First:         local,
+				Last:          local,
+				ByLegislation: make(map[Legislation]int),
+			}
+			operations[s.OperationName] = op
+		}
+		op.Searches++
+		op.ByLegislation[s.Legislation]++
+		if local.Before(op.First) {
+			op.First = local
+		}
+		if local.After(op.Last) {
+			op.Last = local
+		}
+	}
+
+	for _, op := range operations {
+		p.Operations = append(p.Operations, *op)
+	}
+	sort.Slice(p.Operations, func(i, j int) bool {
+		a, b := p.Operations[i], p.Operations[j]
+		if !a.First.Equal(b.First) {
+			return a.First.Before(b.First)
+		}
+		return a.Name < b.Name
+	})
+	return p
+}
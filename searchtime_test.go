@@ -0,0 +1,119 @@
+package ukpolice
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func searchAt(t *testing.T, datetime string, l Legislation, a AgeRange) Search {
+	dt, err := time.Parse(time.RFC3339, datetime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Search{DateTime: dt, Legislation: l, AgeRange: a}
+}
+
+func TestSearchPatterns(t *testing.T) {
+	searches := []Search{
+		// 23:30 UTC on a Saturday in winter is 23:30 on Saturday in London.
+		searchAt(t, "2017-01-14T23:30:00Z", MisuseOfDrugsAct, Age18To24),
+		// 23:30 UTC on a Saturday in summer is 00:30 on Sunday in London.
+		searchAt(t, "2017-07-15T23:30:00Z", MisuseOfDrugsAct, Age10To17),
+		// the day the clocks went forward.
+		searchAt(t, "2017-03-26T01:30:00Z", CriminalJusticeAndPublicOrderAct, Age18To24),
+		{Legislation: MisuseOfDrugsAct},
+	}
+
+	p := SearchPatterns(searches)
+	if p.Searches != 3 {
+		t.Errorf("SearchPatterns counted %d searches, want 3", p.Searches)
+	}
+	if p.HourWeekday[time.Saturday][23] != 1 || p.HourWeekday[time.Sunday][0] != 1 || p.HourWeekday[time.Sunday][2] != 1 {
+		t.Errorf("SearchPatterns returned matrix %v", p.HourWeekday)
+	}
+	wantMonths := map[string]int{"2017-01": 1, "2017-03": 1, "2017-07": 1}
+	if !reflect.DeepEqual(p.ByMonth, wantMonths) {
+		t.Errorf("SearchPatterns returned months %v, want %v", p.ByMonth, wantMonths)
+	}
+	if p.ByMonthOfYear[time.July-1] != 1 {
+		t.Errorf("SearchPatterns returned months of year %v", p.ByMonthOfYear)
+	}
+	if months := p.Months(); !reflect.DeepEqual(months, []string{"2017-01", "2017-03", "2017-07"}) {
+		t.Errorf("TemporalPatterns.Months returned %v", months)
+	}
+
+	p = SearchPatterns(searches, LegislationFilter(MisuseOfDrugsAct), AgeRangeFilter(Age18To24))
+	if p.Searches != 1 || p.HourWeekday[time.Saturday][23] != 1 {
+		t.Errorf("SearchPatterns with filters returned %v", p)
+	}
+}
+
+func TestSearchPatterns_Operations(t *testing.T) {
+	a := searchAt(t, "2017-06-02T12:00:00Z", CriminalJusticeAndPublicOrderAct, "")
+	a.OperationName = "Operation Sceptre"
+	b := searchAt(t, "2017-06-01T12:00:00Z", MisuseOfDrugsAct, "")
+	b.OperationName = "Operation Sceptre"
+	c := searchAt(t, "2017-05-01T12:00:00Z", MisuseOfDrugsAct, "")
+	c.OperationName = "Operation Other"
+
+	p := SearchPatterns([]Search{a, b, c, searchAt(t, "2017-06-01T12:00:00Z", MisuseOfDrugsAct, "")})
+	if len(p.Operations) != 2 {
+		t.Fatalf("SearchPatterns returned %d operations, want 2", len(p.Operations))
+	}
+	op := p.Operations[1]
+	if op.Name != "Operation Sceptre" || op.Searches != 2 || !op.First.Equal(b.DateTime) || !op.Last.Equal(a.DateTime) {
+		t.Errorf("SearchPatterns returned operation %v", op)
+	}
+	if op.First.Location() != londonLocation || op.First.Hour() != 13 {
+		t.Errorf("operation times should be in London time, got %v", op.First)
+	}
+	want := map[Legislation]int{CriminalJusticeAndPublicOrderAct: 1, MisuseOfDrugsAct: 1}
+	if !reflect.DeepEqual(op.ByLegislation, want) {
+		t.Errorf("operation legislation is %v, want %v", op.ByLegislation, want)
+	}
+}
+
+func TestFilterSearches(t *testing.T) {
+	searches := []Search{
+		{Outcome: SearchOutcome{ID: "bu-arrest", Desc: "Arrest", SearchHappened: true}},
+		{Outcome: SearchOutcome{Desc: "Arrest", SearchHappened: true}},
+		{Outcome: SearchOutcome{Desc: "Local resolution", SearchHappened: true}},
+	}
+	if got := FilterSearches(searches, OutcomeFilter("bu-arrest")); len(got) != 1 {
+		t.Errorf("FilterSearches by outcome ID returned %v", got)
+	}
+	if got := FilterSearches(searches, OutcomeFilter("Arrest")); len(got) != 2 {
+		t.Errorf("FilterSearches by outcome description returned %v", got)
+	}
+	if got := FilterSearches(searches); len(got) != 3 {
+		t.Errorf("FilterSearches without filters returned %v", got)
+	}
+}
+
+func TestLondonTZData(t *testing.T) {
+	loc, err := time.LoadLocationFromTZData("Europe/London", londonTZData())
+	if err != nil {
+		t.Fatalf("LoadLocationFromTZData returned error: %v", err)
+	}
+	for _, tc := range []struct {
+		utc, want, zone string
+	}{
+		{"2017-01-14T23:30:00Z", "2017-01-14T23:30:00Z", "GMT"},
+		{"2017-03-26T00:59:59Z", "2017-03-26T00:59:59Z", "GMT"},
+		{"2017-03-26T01:00:00Z", "2017-03-26T02:00:00+01:00", "BST"},
+		{"2017-07-01T12:00:00Z", "2017-07-01T13:00:00+01:00", "BST"},
+		{"2017-10-29T00:59:59Z", "2017-10-29T01:59:59+01:00", "BST"},
+		{"2017-10-29T01:00:00Z", "2017-10-29T01:00:00Z", "GMT"},
+		{"2030-06-01T12:00:00Z", "2030-06-01T13:00:00+01:00", "BST"},
+	} {
+		u, err := time.Parse(time.RFC3339, tc.utc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		local := u.In(loc)
+		if got, zone := local.Format(time.RFC3339), local.Format("MST"); got != tc.want || zone != tc.zone {
+			t.Errorf("%s in London is %s %s, want %s %s", tc.utc, got, zone, tc.want, tc.zone)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ukpolice
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// snapshot is a record of a neighbourhood, or of a force where the
+// neighbourhood is empty, taken at a point in time.
+type snapshot interface {
+	ref() NeighbourhoodRef
+	taken() time.Time
+}
+
+// snapshotStore holds the snapshots of each neighbourhood, oldest first, for
+// PriorityTracker and RosterTracker. It is safe for concurrent use. Snapshots
+// are stored as given, so callers copy any slices or maps they hold.
+type snapshotStore struct {
+	mu      sync.RWMutex
+	history map[NeighbourhoodRef][]snapshot
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{history: make(map[NeighbourhoodRef][]snapshot)}
+}
+
+// record calls diff with the latest snapshot of the neighbourhood of s, or
+// nil if there is none, and stores s if it is the first snapshot or diff
+// returns true. The store is locked while diff runs.
+func (st *snapshotStore) record(s snapshot, diff func(previous snapshot) bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	history := st.history[s.ref()]
+
+	var previous snapshot
+	if n := len(history); n > 0 {
+		previous = history[n-1]
+	}
+	if changed := diff(previous); previous == nil || changed {
+		st.history[s.ref()] = append(history, s)
+	}
+}
+
+// snapshots returns the snapshots of ref, oldest first.
+func (st *snapshotStore) snapshots(ref NeighbourhoodRef) []snapshot {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return append([]snapshot(nil), st.history[ref]...)
+}
+
+// refs returns the neighbourhoods of force with snapshots, ordered by ID.
+func (st *snapshotStore) refs(force string) []NeighbourhoodRef {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	var refs []NeighbourhoodRef
+	for ref := range st.history {
+		if ref.Force == force {
+			refs = append(refs, ref)
+		}
+	}
+	sortRefs(refs)
+	return refs
+}
+
+// all returns every snapshot ordered by force, neighbourhood and time.
+func (st *snapshotStore) all() []snapshot {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	refs := make([]NeighbourhoodRef, 0, len(st.history))
+	for ref := range st.history {
+		refs = append(refs, ref)
+	}
+	sortRefs(refs)
+	var snapshots []snapshot
+	for _, ref := range refs {
+		snapshots = append(snapshots, st.history[ref]...)
+	}
+	return snapshots
+}
+
+// add stores snapshots alongside those already held, keeping each history in
+// time order.
+func (st *snapshotStore) add(snapshots []snapshot) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for _, s := range snapshots {
+		st.history[s.ref()] = append(st.history[s.ref()], s)
+	}
+	for _, history := range st.history {
+		sort.SliceStable(history, func(i, j int) bool { return history[i].taken().Before(history[j].taken()) })
+	}
+}
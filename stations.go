@@ -0,0 +1,272 @@
+package ukpolice
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NeighbourhoodRef identifies a neighbourhood of a force.
+type NeighbourhoodRef struct {
+	Force         string `json:"force"`
+	Neighbourhood string `json:"neighbourhood"`
+}
+
+// Station is a police station, front counter or other location listed by one
+// or more neighbourhoods.
+type Station struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Address     string `json:"address,omitempty"`
+	Postcode    string `json:"postcode,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Point is nil if the API gave no coordinates for the station.
+	Point *Point `json:"point,omitempty"`
+	// Neighbourhoods lists every neighbourhood that lists the station.
+	Neighbourhoods []NeighbourhoodRef `json:"neighbourhoods"`
+}
+
+func (s Station) String() string {
+	return Stringify(s)
+}
+
+// StationDistance is a station and its distance in metres from a point.
+type StationDistance struct {
+	Station  *Station `json:"station"`
+	Distance float64  `json:"distance"`
+}
+
+// StationDirectory aggregates the locations listed by neighbourhoods across
+// forces, merging the same station listed by several neighbourhoods. It is
+// safe for concurrent use.
+type StationDirectory struct {
+	mu       sync.RWMutex
+	stations []*Station
+	// keys holds each station under every key returned by stationKeys for
+	// the listings merged into it.
+	keys map[string]*Station
+}
+
+// NewStationDirectory returns an empty StationDirectory.
+func NewStationDirectory() *StationDirectory {
+	return &StationDirectory{keys: make(map[string]*Station)}
+}
+
+// stationKeys returns the keys identifying a station by its name and
+// postcode and by its name and address, so that a station listed with a
+// postcode by one neighbourhood and without by another is merged.
+func stationKeys(name, address, postcode string) []string {
+	var keys []string
+	if postcode != "" {
+		keys = append(keys, squash(name)+"|postcode|"+squash(postcode))
+	}
+	if address != "" || postcode == "" {
+		keys = append(keys, squash(name)+"|address|"+squash(address))
+	}
+	return keys
+}
+
+// postcodePattern matches a UK postcode at the end of an address.
+var postcodePattern = regexp.MustCompile(`(?i)\b([A-Z]{1,2}[0-9][A-Z0-9]?) ?([0-9][A-Z]{2})$`)
+
+// squash reduces s to its lower case letters and digits.
+func squash(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// tidyAddress joins the lines and comma separated parts of an address,
+// dropping empty parts, e.g. "74 Belgrave Gate\n, Leicester" becomes
+// "74 Belgrave Gate, Leicester".
+func tidyAddress(s string) string {
+	var parts []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Add records the locations listed by a neighbourhood of force.
+func (d *StationDirectory) Add(force string, n Neighbourhood) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ref := NeighbourhoodRef{Force: force, Neighbourhood: n.ID}
+	for _, l := range n.Locations {
+		name := strings.TrimSpace(l.Name)
+		address := tidyAddress(l.Address)
+		postcode := strings.ToUpper(strings.Join(strings.Fields(l.Postcode), " "))
+		if name == "" && address == "" && postcode == "" {
+			continue
+		}
+		if m := postcodePattern.FindStringSubmatch(address); postcode == "" && m != nil {
+			postcode = strings.ToUpper(m[1] + " " + m[2])
+			address = strings.TrimRight(strings.TrimSpace(address[:len(address)-len(m[0])]), ",")
+		}
+
+		keys := stationKeys(name, address, postcode)
+		var s *Station
+		for _, k := range keys {
+			if s = d.keys[k]; s != nil {
+				break
+			}
+		}
+		if s == nil {
+			s = &Station{Name: name}
+			d.stations = append(d.stations, s)
+		}
+		for _, k := range keys {
+			if d.keys[k] == nil {
+				d.keys[k] = s
+			}
+		}
+		if s.Address == "" {
+			s.Address = address
+		}
+		if s.Postcode == "" {
+			s.Postcode = postcode
+		}
+		if s.Type == "" {
+			s.Type = strings.TrimSpace(l.Type)
+		}
+		if s.Description == "" {
+			s.Description = strings.TrimSpace(l.Description)
+		}
+		if s.Point == nil {
+			if p, err := l.Point(); err == nil {
+				s.Point = &p
+			}
+		}
+		if !hasRef(s.Neighbourhoods, ref) {
+			s.Neighbourhoods = append(s.Neighbourhoods, ref)
+		}
+	}
+}
+
+func hasRef(refs []NeighbourhoodRef, ref NeighbourhoodRef) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// AddCrawl records the locations listed by every neighbourhood in a force
+// crawl.
+func (d *StationDirectory) AddCrawl(crawl *ForceCrawl) {
+	for _, n := range crawl.Neighbourhoods {
+		d.Add(crawl.Force, n.Neighbourhood)
+	}
+}
+
+// Len returns the number of stations in the directory.
+func (d *StationDirectory) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.stations)
+}
+
+// Stations returns a copy of every station, ordered by name and postcode.
+// Changes to the copies do not affect the directory.
+func (d *StationDirectory) Stations() []*Station {
+	return d.filter(func(*Station) bool { return true })
+}
+
+// OfType returns the stations of any of the provided types, such as
+// "station", ignoring case.
+func (d *StationDirectory) OfType(types ...string) []*Station {
+	return d.filter(typeFilter(types))
+}
+
+func typeFilter(types []string) func(*Station) bool {
+	return func(s *Station) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, t := range types {
+			if strings.EqualFold(s.Type, t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (d *StationDirectory) filter(keep func(*Station) bool) []*Station {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var stations []*Station
+	for _, s := range d.stations {
+		if keep(s) {
+			c := *s
+			c.Neighbourhoods = append([]NeighbourhoodRef(nil), s.Neighbourhoods...)
+			if s.Point != nil {
+				p := *s.Point
+				c.Point = &p
+			}
+			stations = append(stations, &c)
+		}
+	}
+	sort.Slice(stations, func(i, j int) bool {
+		if stations[i].Name != stations[j].Name {
+			return stations[i].Name < stations[j].Name
+		}
+		return stations[i].Postcode < stations[j].Postcode
+	})
+	return stations
+}
+
+// Nearest returns up to n stations with coordinates closest to p, nearest
+// first, or every station with coordinates if n is not positive. If types
+// are provided only stations of those types are considered.
+func (d *StationDirectory) Nearest(p Point, n int, types ...string) []StationDistance {
+	matches := d.distances(p, typeFilter(types))
+	if n > 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// Within returns the stations within radius metres of p, nearest first. If
+// types are provided only stations of those types are considered.
+func (d *StationDirectory) Within(p Point, radius float64, types ...string) []StationDistance {
+	var within []StationDistance
+	for _, m := range d.distances(p, typeFilter(types)) {
+		if m.Distance > radius {
+			break
+		}
+		within = append(within, m)
+	}
+	return within
+}
+
+func (d *StationDirectory) distances(p Point, keep func(*Station) bool) []StationDistance {
+	var matches []StationDistance
+	for _, s := range d.filter(keep) {
+		if s.Point != nil {
+			matches = append(matches, StationDistance{Station: s, Distance: distance(p, *s.Point)})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	return matches
+}
+
+// InPolygon returns the stations inside polygon, such as a neighbourhood
+// boundary. If types are provided only stations of those types are
+// considered.
+func (d *StationDirectory) InPolygon(polygon []Point, types ...string) []*Station {
+	isType := typeFilter(types)
+	return d.filter(func(s *Station) bool {
+		return s.Point != nil && isType(s) && pointInPolygon(*s.Point, polygon)
+	})
+}
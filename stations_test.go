@@ -0,0 +1,97 @@
+package ukpolice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testStationDirectory() *StationDirectory {
+	d := NewStationDirectory()
+	d.Add("leicestershire", Neighbourhood{ID: "NC04", Locations: []Location{
+		{Name: "Mansfield House", Postcode: "LE1 3GG", Address: "74 Belgrave Gate\n, Leicester", Type: "station"},
+		{Name: "Town Hall", Address: "Town Hall Square, Leicester", Type: "front counter",
+			Latitude: "52.6339", Longitude: "-1.1335"},
+	}})
+	d.Add("leicestershire", Neighbourhood{ID: "NC66", Locations: []Location{
+		{Name: "Mansfield  House", Postcode: "le1 3gg", Address: "74 Belgrave Gate, Leicester", Type: "station",
+			Latitude: "52.6397", Longitude: "-1.1317"},
+		{Name: "Euston Street", Postcode: "LE2 7ND", Type: "station", Latitude: "52.6205", Longitude: "-1.1449"},
+	}})
+	d.Add("leicestershire", Neighbourhood{ID: "NC04", Locations: []Location{{Name: "", Address: "\n"}}})
+	// the same stations without a postcode, or with it in the address.
+	d.Add("leicestershire", Neighbourhood{ID: "NC67", Locations: []Location{
+		{Name: "Mansfield House", Address: "74 Belgrave Gate, Leicester", Type: "station"},
+		{Name: "Euston Street", Address: "Euston Street, Leicester, LE2 7ND", Type: "station"},
+	}})
+	return d
+}
+
+func TestStationDirectory_Add(t *testing.T) {
+	d := testStationDirectory()
+	if d.Len() != 3 {
+		t.Fatalf("StationDirectory.Len returned %d, want 3", d.Len())
+	}
+
+	want := &Station{
+		Name:     "Mansfield House",
+		Type:     "station",
+		Address:  "74 Belgrave Gate, Leicester",
+		Postcode: "LE1 3GG",
+		Point:    &Point{Latitude: 52.6397, Longitude: -1.1317},
+		Neighbourhoods: []NeighbourhoodRef{
+			{Force: "leicestershire", Neighbourhood: "NC04"},
+			{Force: "leicestershire", Neighbourhood: "NC66"},
+			{Force: "leicestershire", Neighbourhood: "NC67"},
+		},
+	}
+	if got := d.Stations()[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("StationDirectory.Stations()[1] is %v, want %v", got, want)
+	}
+	if got := d.Stations()[0]; got.Address != "Euston Street, Leicester" || len(got.Neighbourhoods) != 2 {
+		t.Errorf("StationDirectory.Stations()[0] is %v, want Euston Street listed twice", got)
+	}
+
+	d.Stations()[1].Point.Latitude = 0
+	if got := d.Stations()[1].Point.Latitude; got != 52.6397 {
+		t.Errorf("changing a returned station moved the station in the directory to %v", got)
+	}
+}
+
+func TestStationDirectory_queries(t *testing.T) {
+	d := testStationDirectory()
+	centre := Point{Latitude: 52.6345, Longitude: -1.1320}
+
+	var names []string
+	for _, m := range d.Nearest(centre, 2) {
+		names = append(names, m.Station.Name)
+	}
+	if want := []string{"Town Hall", "Mansfield House"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("StationDirectory.Nearest returned %v, want %v", names, want)
+	}
+
+	if got := d.Nearest(centre, -1); len(got) != 3 {
+		t.Errorf("StationDirectory.Nearest with a negative n returned %v, want every station", got)
+	}
+
+	if got := d.Nearest(centre, 1, "station"); len(got) != 1 || got[0].Station.Name != "Mansfield House" {
+		t.Errorf("StationDirectory.Nearest of type station returned %v", got)
+	}
+
+	within := d.Within(centre, 1000)
+	if len(within) != 2 || within[1].Distance > 1000 {
+		t.Errorf("StationDirectory.Within returned %v", within)
+	}
+
+	if got := d.OfType("Front Counter"); len(got) != 1 || got[0].Name != "Town Hall" {
+		t.Errorf("StationDirectory.OfType returned %v", got)
+	}
+
+	polygon := []Point{{52.63, -1.14}, {52.63, -1.12}, {52.645, -1.12}, {52.645, -1.14}}
+	names = nil
+	for _, s := range d.InPolygon(polygon) {
+		names = append(names, s.Name)
+	}
+	if want := []string{"Mansfield House", "Town Hall"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("StationDirectory.InPolygon returned %v, want %v", names, want)
+	}
+}
@@ -2,6 +2,8 @@ package ukpolice
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -11,22 +13,22 @@ type StopAndSearchService service
 
 // Search holds information relating to individual stop and searches.
 type Search struct {
-	ID                             int           `json:"id"`
-	AgeRange                       string        `json:"age_range"`
-	Type                           string        `json:"type"`
-	Gender                         string        `json:"gender"`
-	Outcome                        SearchOutcome `json:"outcome"`
-	InvolvedPerson                 bool          `json:"involved_person"`
-	SelfDefinedEthnicity           string        `json:"self_defined_ethnicity"`
-	OfficerDefinedEthnicity        string        `json:"officer_defined_ethnicity"`
-	DateTime                       time.Time     `json:"datetime"`
-	RemovalOfMoreThanOuterClothing bool          `json:"removal_of_more_than_outer_clothing"`
-	Location                       Location      `json:"location"`
-	Operation                      bool          `json:"operation"`
-	OperationName                  string        `json:"operation_name"`
-	OutcomeLinkedToObject          bool          `json:"outcome_linked_to_object_of_search"`
-	ObjectOfSearch                 string        `json:"object_of_search"`
-	Legislation                    string        `json:"legislation"`
+	ID                             int                  `json:"id"`
+	AgeRange                       AgeRange             `json:"age_range"`
+	Type                           SearchType           `json:"type"`
+	Gender                         Gender               `json:"gender"`
+	Outcome                        SearchOutcome        `json:"outcome"`
+	InvolvedPerson                 bool                 `json:"involved_person"`
+	SelfDefinedEthnicity           SelfDefinedEthnicity `json:"self_defined_ethnicity"`
+	OfficerDefinedEthnicity        EthnicGroup          `json:"officer_defined_ethnicity"`
+	DateTime                       time.Time            `json:"datetime"`
+	RemovalOfMoreThanOuterClothing bool                 `json:"removal_of_more_than_outer_clothing"`
+	Location                       Location             `json:"location"`
+	Operation                      bool                 `json:"operation"`
+	OperationName                  string               `json:"operation_name"`
+	OutcomeLinkedToObject          *bool                `json:"outcome_linked_to_object_of_search"`
+	ObjectOfSearch                 ObjectOfSearch       `json:"object_of_search"`
+	Legislation                    Legislation          `json:"legislation"`
 	// Force is not supplied natively by the API - if you want to record which
 	// force a search belongs to update this field after fetching.
 	Force string `json:"force"`
@@ -36,26 +38,115 @@ func (s Search) String() string {
 	return Stringify(s)
 }
 
+// searchJSON mirrors Search without its methods so that Search can customise
+// its JSON encoding.
+type searchJSON Search
+
+// outcomeObject is the structured outcome supplied alongside the outcome
+// description.
+type outcomeObject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It merges the
+// outcome_object supplied by the API into Outcome.
+func (s *Search) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		searchJSON
+		OutcomeObject *outcomeObject `json:"outcome_object"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	*s = Search(aux.searchJSON)
+	if aux.OutcomeObject != nil {
+		s.Outcome.ID = aux.OutcomeObject.ID
+		s.Outcome.Name = aux.OutcomeObject.Name
+		if s.Outcome.Desc == "" {
+			s.Outcome.Desc = aux.OutcomeObject.Name
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. It produces the same
+// structure as the API, writing Outcome as outcome and outcome_object, so
+// that a Search survives a round trip unchanged.
+func (s Search) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		searchJSON
+		Outcome       interface{}    `json:"outcome"`
+		OutcomeObject *outcomeObject `json:"outcome_object"`
+	}{searchJSON: searchJSON(s)}
+
+	switch o := s.Outcome; {
+	case o.Missing:
+		aux.Outcome = nil
+	case !o.SearchHappened:
+		aux.Outcome = false
+	default:
+		aux.Outcome = o.Desc
+	}
+	if s.Outcome.ID != "" || s.Outcome.Name != "" {
+		aux.OutcomeObject = &outcomeObject{ID: s.Outcome.ID, Name: s.Outcome.Name}
+	}
+	return json.Marshal(aux)
+}
+
 // SearchOutcome holds details of search outcomes. The 'outcome' result provided
-// by the data.police.uk api returns both string and bool types, this struct
-// and the custom UnmarshalJSON satisfy type security.
+// by the data.police.uk api is either a description, false or null, and the
+// outcome ID and name are supplied separately in 'outcome_object'; Search
+// merges the two.
+//
+// On its own a SearchOutcome is encoded as an object with the fields below.
+// It can be decoded from that object or from the API's outcome value.
 type SearchOutcome struct {
-	Desc           string `json:"outcome_desc"`
-	SearchHappened bool   `json:"searched"`
+	// ID identifies the outcome, e.g. "bu-arrest". It is empty if the API did
+	// not supply an outcome object.
+	ID string `json:"id,omitempty"`
+	// Name is the name given in the outcome object, e.g. "Arrest".
+	Name string `json:"name,omitempty"`
+	// Desc is the outcome as described by the API, e.g. "Arrest". Name is used
+	// if the API gave no description.
+	Desc string `json:"desc,omitempty"`
+	// SearchHappened is false when the API reports the outcome as false or
+	// null.
+	SearchHappened bool `json:"searched"`
+	// Missing is true when the API reports the outcome as null.
+	Missing bool `json:"missing,omitempty"`
 }
 
 func (o SearchOutcome) String() string {
 	return Stringify(o)
 }
 
-// UnmarshalJSON implements the json.Unmarshaller interface.
+// searchOutcomeJSON mirrors SearchOutcome without its methods.
+type searchOutcomeJSON SearchOutcome
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
 func (o *SearchOutcome) UnmarshalJSON(b []byte) error {
-	s := string(b)
-	if s == "false" {
-		*o = SearchOutcome{SearchHappened: false, Desc: ""}
-	} else {
-		*o = SearchOutcome{SearchHappened: true, Desc: s}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
 
+	switch v := v.(type) {
+	case nil:
+		*o = SearchOutcome{Missing: true}
+	case bool:
+		*o = SearchOutcome{SearchHappened: v}
+	case string:
+		*o = SearchOutcome{SearchHappened: true, Desc: v}
+	case map[string]interface{}:
+		var aux searchOutcomeJSON
+		if err := json.Unmarshal(b, &aux); err != nil {
+			return err
+		}
+		*o = SearchOutcome(aux)
+	default:
+		return fmt.Errorf("cannot unmarshal %s into a search outcome", b)
 	}
 	return nil
 }
@@ -76,6 +167,7 @@ func (s *StopAndSearchService) GetStopAndSearchesByArea(ctx context.Context, opt
 	if err != nil {
 		return nil, nil, err
 	}
+	s.api.Streets.AddSearches(searches...)
 	return searches, resp, nil
 }
 
@@ -94,6 +186,7 @@ func (s *StopAndSearchService) GetStopAndSearchesByLocation(ctx context.Context,
 	if err != nil {
 		return nil, nil, err
 	}
+	s.api.Streets.AddSearches(searches...)
 	return searches, resp, nil
 }
 
@@ -113,6 +206,7 @@ func (s *StopAndSearchService) GetStopAndSearchesWithNoLocation(ctx context.Cont
 	if err != nil {
 		return nil, nil, err
 	}
+	s.api.Streets.AddSearches(searches...)
 	return searches, resp, nil
 }
 
@@ -131,5 +225,6 @@ func (s *StopAndSearchService) GetStopAndSearchesByForce(ctx context.Context, op
 	if err != nil {
 		return nil, nil, err
 	}
+	s.api.Streets.AddSearches(searches...)
 	return searches, resp, nil
 }
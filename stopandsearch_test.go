@@ -180,3 +180,163 @@ func TestCrimeService_GetStopAndSearchesByForce(t *testing.T) {
 		t.Errorf("StopAndSearch.GetStopAndSearchesByForce returned %v, want %v", searches, want)
 	}
 }
+
+func TestSearch_UnmarshalOutcome(t *testing.T) {
+	tt := []struct {
+		name   string
+		raw    string
+		want   SearchOutcome
+		linked *bool
+	}{
+		{
+			"Outcome object",
+			`{"outcome": "Arrest", "outcome_object": {"id": "bu-arrest", "name": "Arrest"}, "outcome_linked_to_object_of_search": true}`,
+			SearchOutcome{ID: "bu-arrest", Name: "Arrest", Desc: "Arrest", SearchHappened: true},
+			Bool(true),
+		},
+		{
+			"Description only",
+			`{"outcome": "Local resolution", "outcome_linked_to_object_of_search": false}`,
+			SearchOutcome{Desc: "Local resolution", SearchHappened: true},
+			Bool(false),
+		},
+		{
+			"False",
+			`{"outcome": false, "outcome_object": null, "outcome_linked_to_object_of_search": null}`,
+			SearchOutcome{},
+			nil,
+		},
+		{
+			"Null",
+			`{"outcome": null}`,
+			SearchOutcome{Missing: true},
+			nil,
+		},
+		{
+			"Name only",
+			`{"outcome": false, "outcome_object": {"id": "bu-no-further-action", "name": "A no further action disposal"}}`,
+			SearchOutcome{ID: "bu-no-further-action", Name: "A no further action disposal", Desc: "A no further action disposal"},
+			nil,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var s Search
+			if err := json.Unmarshal([]byte(tc.raw), &s); err != nil {
+				t.Fatalf("could not unmarshal json: '%s'", err)
+			}
+			if s.Outcome != tc.want {
+				t.Errorf("Search.Outcome is %v, want %v", s.Outcome, tc.want)
+			}
+			if !reflect.DeepEqual(s.OutcomeLinkedToObject, tc.linked) {
+				t.Errorf("Search.OutcomeLinkedToObject is %v, want %v", s.OutcomeLinkedToObject, tc.linked)
+			}
+		})
+	}
+
+	var o SearchOutcome
+	if err := json.Unmarshal([]byte(`3`), &o); err == nil {
+		t.Errorf("SearchOutcome.UnmarshalJSON should have returned an error for a number")
+	}
+}
+
+func TestSearchOutcome_RoundTrip(t *testing.T) {
+	for _, raw := range []string{
+		`{"outcome": "Arrest", "outcome_object": {"id": "bu-arrest", "name": "Suspect arrested"}}`,
+		`{"outcome": "Local resolution"}`,
+		`{"outcome": false}`,
+		`{"outcome": null}`,
+		`{"outcome": false, "outcome_object": {"id": "bu-no-further-action", "name": "A no further action disposal"}}`,
+	} {
+		var first Search
+		if err := json.Unmarshal([]byte(raw), &first); err != nil {
+			t.Fatalf("could not unmarshal %s: '%s'", raw, err)
+		}
+		b, err := json.Marshal(first)
+		if err != nil {
+			t.Fatalf("could not marshal search: '%s'", err)
+		}
+		var second Search
+		if err := json.Unmarshal(b, &second); err != nil {
+			t.Fatalf("could not unmarshal %s: '%s'", b, err)
+		}
+		if first.Outcome != second.Outcome {
+			t.Errorf("Search.Outcome of %s became %v after a round trip, want %v", raw, second.Outcome, first.Outcome)
+		}
+
+		// a SearchOutcome encoded on its own keeps every field.
+		b, err = json.Marshal(first.Outcome)
+		if err != nil {
+			t.Fatalf("could not marshal outcome: '%s'", err)
+		}
+		var o SearchOutcome
+		if err := json.Unmarshal(b, &o); err != nil {
+			t.Fatalf("could not unmarshal %s: '%s'", b, err)
+		}
+		if o != first.Outcome {
+			t.Errorf("SearchOutcome %s decoded as %v, want %v", b, o, first.Outcome)
+		}
+	}
+}
+
+func TestSearch_RoundTrip(t *testing.T) {
+	raw := `{
+		"age_range": "18-24",
+		"self_defined_ethnicity": "White - English/Welsh/Scottish/Northern Irish/British",
+		"outcome_linked_to_object_of_search": null,
+		"datetime": "2019-01-14T20:50:00+00:00",
+		"removal_of_more_than_outer_clothing": false,
+		"outcome_object": {"id": "bu-no-further-action", "name": "A no further action disposal"},
+		"operation": false,
+		"officer_defined_ethnicity": "White",
+		"object_of_search": "Controlled drugs",
+		"involved_person": true,
+		"gender": "Male",
+		"legislation": "Misuse of Drugs Act 1971 (section 23)",
+		"location": {
+			"latitude": "52.634407",
+			"street": {"id": 883407, "name": "On or near Shopping Area"},
+			"longitude": "-1.133653"
+		},
+		"outcome": "A no further action disposal",
+		"type": "Person search",
+		"operation_name": null
+	}`
+
+	var first Search
+	if err := json.Unmarshal([]byte(raw), &first); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+	b, err := json.Marshal(first)
+	if err != nil {
+		t.Fatalf("could not marshal search: '%s'", err)
+	}
+	var second Search
+	if err := json.Unmarshal(b, &second); err != nil {
+		t.Fatalf("could not unmarshal json: '%s'", err)
+	}
+	if !first.DateTime.Equal(second.DateTime) {
+		t.Errorf("Search.DateTime did not survive a round trip: got %v, want %v", second.DateTime, first.DateTime)
+	}
+	// times may differ in representation while referring to the same instant.
+	second.DateTime = first.DateTime
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Search did not survive a round trip: got %v, want %v", second, first)
+	}
+
+	var fields map[string]interface{}
+	json.Unmarshal(b, &fields)
+	if fields["outcome"] != "A no further action disposal" || fields["outcome_linked_to_object_of_search"] != nil {
+		t.Errorf("Search.MarshalJSON returned %s", b)
+	}
+	object, _ := fields["outcome_object"].(map[string]interface{})
+	if object["id"] != "bu-no-further-action" {
+		t.Errorf("Search.MarshalJSON returned outcome_object %v", fields["outcome_object"])
+	}
+
+	b, _ = json.Marshal(Search{})
+	json.Unmarshal(b, &fields)
+	if fields["outcome"] != false || fields["outcome_object"] != nil {
+		t.Errorf("Search.MarshalJSON returned %s for an empty search", b)
+	}
+}
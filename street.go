@@ -0,0 +1,284 @@
+package ukpolice
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Street holds everything the registry has seen about a street.
+type Street struct {
+	// ID is the street ID, which can be used with WithLocationID.
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Points holds every distinct snap point seen for the street.
+	Points []Point `json:"points"`
+	// Crimes holds the number of crimes seen on the street by category.
+	Crimes   map[string]int `json:"crimes"`
+	Outcomes int            `json:"outcomes"`
+	Searches int            `json:"searches"`
+}
+
+func (s Street) String() string {
+	return Stringify(s)
+}
+
+// StreetCount holds the number of crimes seen on a street.
+type StreetCount struct {
+	Street *Street
+	Count  int
+}
+
+// StreetRegistry accumulates the streets referenced by crimes, outcomes and
+// stop and searches. It is safe for concurrent use. The zero value is an
+// empty registry, and the methods reading a registry treat nil as empty.
+//
+// Assign a registry to Client.Streets to have it record every street the
+// client receives from the API.
+type StreetRegistry struct {
+	mu      sync.RWMutex
+	streets map[uint]*Street
+}
+
+// NewStreetRegistry returns an empty StreetRegistry.
+func NewStreetRegistry() *StreetRegistry {
+	return &StreetRegistry{streets: make(map[uint]*Street)}
+}
+
+// record adds a sighting of the street at l and returns it, or nil if l has no
+// street. The caller must hold the write lock.
+func (r *StreetRegistry) record(l Location) *Street {
+	if l.Street.ID == 0 {
+		return nil
+	}
+	if r.streets == nil {
+		r.streets = make(map[uint]*Street)
+	}
+	s, ok := r.streets[l.Street.ID]
+	if !ok {
+		s = &Street{ID: l.Street.ID, Crimes: make(map[string]int)}
+		r.streets[l.Street.ID] = s
+	}
+	if l.Street.Name != "" {
+		s.Name = l.Street.Name
+	}
+	if p, err := l.Point(); err == nil {
+		seen := false
+		for _, sp := range s.Points {
+			if sp == p {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			s.Points = append(s.Points, p)
+		}
+	}
+	return s
+}
+
+// AddCrimes records the street of each crime and counts it by category. A nil
+// registry ignores the crimes.
+func (r *StreetRegistry) AddCrimes(crimes ...Crime) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range crimes {
+		if s := r.record(c.Location); s != nil {
+			s.Crimes[c.Category]++
+		}
+	}
+}
+
+// AddOutcomes records the street of the crime behind each outcome. A nil
+// registry ignores the outcomes.
+func (r *StreetRegistry) AddOutcomes(outcomes ...Outcome) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range outcomes {
+		if s := r.record(o.Crime.Location); s != nil {
+			s.Outcomes++
+		}
+	}
+}
+
+// AddSearches records the street of each stop and search. A nil registry
+// ignores the searches.
+func (r *StreetRegistry) AddSearches(searches ...Search) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, search := range searches {
+		if s := r.record(search.Location); s != nil {
+			s.Searches++
+		}
+	}
+}
+
+// Len returns the number of streets in the registry.
+func (r *StreetRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.streets)
+}
+
+// Street returns a copy of the street with the provided ID.
+func (r *StreetRegistry) Street(id uint) (*Street, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.streets[id]
+	if !ok {
+		return nil, false
+	}
+	return s.copy(), true
+}
+
+func (s *Street) copy() *Street {
+	c := *s
+	c.Points = append([]Point(nil), s.Points...)
+	c.Crimes = make(map[string]int, len(s.Crimes))
+	for k, v := range s.Crimes {
+		c.Crimes[k] = v
+	}
+	return &c
+}
+
+// Streets returns a copy of every street in the registry ordered by ID.
+func (r *StreetRegistry) Streets() []*Street {
+	if r == nil {
+		return []*Street{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	streets := make([]*Street, 0, len(r.streets))
+	for _, s := range r.streets {
+		streets = append(streets, s.copy())
+	}
+	sort.Slice(streets, func(i, j int) bool { return streets[i].ID < streets[j].ID })
+	return streets
+}
+
+// Count returns the number of crimes of the provided category seen on the
+// street with the provided ID. AllCrime counts every category.
+func (r *StreetRegistry) Count(id uint, category string) int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.streets[id]
+	if !ok {
+		return 0
+	}
+	return s.count(category)
+}
+
+func (s *Street) count(category string) int {
+	if category != AllCrime {
+		return s.Crimes[category]
+	}
+	var n int
+	for _, c := range s.Crimes {
+		n += c
+	}
+	return n
+}
+
+// Top returns up to n streets with the most crimes of the provided category,
+// ordered by descending count then ID, or every such street if n is not
+// positive. AllCrime counts every category.
+func (r *StreetRegistry) Top(category string, n int) []StreetCount {
+	var counts []StreetCount
+	for _, s := range r.Streets() {
+		if c := s.count(category); c > 0 {
+			counts = append(counts, StreetCount{Street: s, Count: c})
+		}
+	}
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].Count > counts[j].Count })
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// Candidates returns the streets whose name contains query, ignoring case and
+// the "On or near" prefix used by the API. Their IDs can be used with
+// WithLocationID.
+func (r *StreetRegistry) Candidates(query string) []*Street {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []*Street
+	for _, s := range r.Streets() {
+		name := strings.ToLower(strings.TrimPrefix(s.Name, "On or near "))
+		if strings.Contains(name, query) {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+// Encode writes the registry to w as JSON.
+func (r *StreetRegistry) Encode(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r.Streets())
+}
+
+// Decode merges the JSON written by Encode into the registry, replacing any
+// streets with the same ID.
+func (r *StreetRegistry) Decode(rd io.Reader) error {
+	var streets []*Street
+	if err := json.NewDecoder(rd).Decode(&streets); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.streets == nil {
+		r.streets = make(map[uint]*Street)
+	}
+	for _, s := range streets {
+		if s.Crimes == nil {
+			s.Crimes = make(map[string]int)
+		}
+		r.streets[s.ID] = s
+	}
+	return nil
+}
+
+// Save writes the registry to the file at path.
+func (r *StreetRegistry) Save(path string) error {
+	var buf bytes.Buffer
+	if err := r.Encode(&buf); err != nil {
+		return err
+	}
+	return writeFile(path, buf.Bytes())
+}
+
+// LoadStreetRegistry reads a registry saved with Save.
+func LoadStreetRegistry(path string) (*StreetRegistry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := NewStreetRegistry()
+	if err := r.Decode(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
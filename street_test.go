@@ -0,0 +1,145 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func streetCrime(category string, id uint, name, lat, lng string) Crime {
+	c := Crime{Category: category}
+	c.Location.Latitude, c.Location.Longitude = lat, lng
+	c.Location.Street.ID, c.Location.Street.Name = id, name
+	return c
+}
+
+func TestStreetRegistry(t *testing.T) {
+	r := NewStreetRegistry()
+	r.AddCrimes(
+		streetCrime("burglary", 1, "On or near Wharf Street North", "52.64", "-1.12"),
+		streetCrime("burglary", 1, "On or near Wharf Street North", "52.64", "-1.12"),
+		streetCrime("drugs", 1, "On or near Wharf Street North", "52.65", "-1.12"),
+		streetCrime("drugs", 2, "On or near Kate Street", "52.63", "-1.14"),
+		streetCrime("drugs", 2, "On or near Kate Street", "52.63", "-1.14"),
+		streetCrime("drugs", 2, "On or near Kate Street", "52.63", "-1.14"),
+		Crime{Category: "other-crime"},
+	)
+	r.AddOutcomes(Outcome{Crime: streetCrime("drugs", 2, "On or near Kate Street", "52.63", "-1.14")})
+	r.AddSearches(Search{Location: streetCrime("", 3, "On or near Shopping Area", "52.634", "-1.133").Location})
+
+	if r.Len() != 3 {
+		t.Errorf("StreetRegistry.Len returned %d, want 3", r.Len())
+	}
+
+	s, ok := r.Street(1)
+	if !ok {
+		t.Fatalf("StreetRegistry.Street did not find street 1")
+	}
+	want := &Street{
+		ID:     1,
+		Name:   "On or near Wharf Street North",
+		Points: []Point{{52.64, -1.12}, {52.65, -1.12}},
+		Crimes: map[string]int{"burglary": 2, "drugs": 1},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("StreetRegistry.Street returned %v, want %v", s, want)
+	}
+
+	if n := r.Count(1, AllCrime); n != 3 {
+		t.Errorf("StreetRegistry.Count returned %d, want 3", n)
+	}
+
+	top := r.Top("drugs", 1)
+	if len(top) != 1 || top[0].Street.ID != 2 || top[0].Count != 3 {
+		t.Errorf("StreetRegistry.Top returned %v, want street 2 with 3 crimes", top)
+	}
+	top = r.Top(AllCrime, 5)
+	if len(top) != 2 || top[0].Street.ID != 1 || top[1].Street.ID != 2 {
+		t.Errorf("StreetRegistry.Top returned %v, want streets 1 and 2", top)
+	}
+	if top = r.Top(AllCrime, -1); len(top) != 2 {
+		t.Errorf("StreetRegistry.Top with a negative n returned %v, want every street", top)
+	}
+
+	candidates := r.Candidates("kate")
+	if len(candidates) != 1 || candidates[0].ID != 2 || candidates[0].Outcomes != 1 {
+		t.Errorf("StreetRegistry.Candidates returned %v, want street 2", candidates)
+	}
+	if candidates := r.Candidates("on or near"); len(candidates) != 0 {
+		t.Errorf("StreetRegistry.Candidates matched the API prefix: %v", candidates)
+	}
+	if s, _ := r.Street(3); s.Searches != 1 {
+		t.Errorf("StreetRegistry recorded %d searches, want 1", s.Searches)
+	}
+}
+
+func TestStreetRegistry_zeroAndNil(t *testing.T) {
+	var r StreetRegistry
+	r.AddCrimes(streetCrime("burglary", 1, "On or near Kate Street", "52.63", "-1.14"))
+	if r.Len() != 1 || r.Count(1, "burglary") != 1 {
+		t.Errorf("zero StreetRegistry holds %v", r.Streets())
+	}
+
+	var nilRegistry *StreetRegistry
+	nilRegistry.AddCrimes(streetCrime("burglary", 1, "On or near Kate Street", "52.63", "-1.14"))
+	if nilRegistry.Len() != 0 || nilRegistry.Count(1, AllCrime) != 0 || len(nilRegistry.Streets()) != 0 {
+		t.Errorf("nil StreetRegistry is not empty")
+	}
+	if s, ok := nilRegistry.Street(1); ok || s != nil {
+		t.Errorf("nil StreetRegistry.Street returned %v, %v", s, ok)
+	}
+	if top := nilRegistry.Top(AllCrime, 0); len(top) != 0 {
+		t.Errorf("nil StreetRegistry.Top returned %v", top)
+	}
+}
+
+func TestStreetRegistry_SaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := NewStreetRegistry()
+	r.AddCrimes(streetCrime("burglary", 1, "On or near Wharf Street North", "52.64", "-1.12"))
+
+	path := filepath.Join(dir, "streets.json")
+	if err := r.Save(path); err != nil {
+		t.Fatalf("StreetRegistry.Save returned error: '%s'", err)
+	}
+	loaded, err := LoadStreetRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadStreetRegistry returned error: '%s'", err)
+	}
+	if !reflect.DeepEqual(loaded.Streets(), r.Streets()) {
+		t.Errorf("LoadStreetRegistry returned %v, want %v", loaded.Streets(), r.Streets())
+	}
+}
+
+func TestClient_Streets(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Streets = NewStreetRegistry()
+
+	mux.HandleFunc("/crimes-street/all-crime", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rawCrime)
+	})
+	mux.HandleFunc("/stops-street", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rawSearch)
+	})
+
+	client.Crime.GetStreetLevelCrimes(context.Background(), WithLatLong("52.629729", "-1.131592"))
+	client.StopAndSearch.GetStopAndSearchesByArea(context.Background(), WithLatLong("52.629729", "-1.131592"))
+
+	if _, ok := client.Streets.Street(884343); !ok {
+		t.Errorf("Client.Streets did not record the street of a crime")
+	}
+	if s, ok := client.Streets.Street(883407); !ok || s.Searches != 1 {
+		t.Errorf("Client.Streets did not record the street of a search")
+	}
+}
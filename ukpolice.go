@@ -3,9 +3,11 @@ package ukpolice
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -33,7 +35,9 @@ type service struct {
 	api *Client
 }
 
-// Client manages communication with the data.police.uk API
+// Client manages communication with the data.police.uk API. Service methods
+// return a *StatusError for responses other than 2xx, including the 404 the
+// API sends for unknown forces and neighbourhoods, rather than empty values.
 type Client struct {
 	client *http.Client // HTTP client used to communicate with the API
 
@@ -45,12 +49,22 @@ type Client struct {
 
 	common service // Reuse a single struct instead of allocating one for each service.
 
+	availability availabilityCache // Most recent availability index.
+
 	// Services used for talking to different parts of the data.police.uk API
 	Availability  *AvailabilityService
 	Force         *ForceService
 	Crime         *CrimeService
 	Neighborhood  *NeighbourhoodService
 	StopAndSearch *StopAndSearchService
+
+	// Forces, if set, is used to reject requests for unknown forces before
+	// they are sent.
+	Forces *ForceDirectory
+
+	// Streets, if set, records the street of every crime, outcome and stop
+	// and search returned by the API.
+	Streets *StreetRegistry
 }
 
 // NewClient returns a new data.police.uk API client. If a nil httpClient is
@@ -93,6 +107,11 @@ func (api *Client) NewRequest(method, url string, body interface{}) (*http.Reque
 	if err != nil {
 		return nil, err
 	}
+	if force := u.Query().Get("force"); force != "" {
+		if err := api.Forces.Validate(force); err != nil {
+			return nil, err
+		}
+	}
 	req, err := http.NewRequest(method, u.String(), nil)
 	if err != nil {
 		return nil, err
@@ -117,7 +136,37 @@ func makeResponse(r *http.Response) *Response {
 	return &Response{Response: r}
 }
 
-// Do carries out a request and stores the result in v.
+// StatusError is returned by Do when the API responds with a status other
+// than 2xx, such as 404 for an unknown neighbourhood or 503 when a query
+// would return too many crimes.
+type StatusError struct {
+	Response *http.Response
+	// RetryAfter is the delay requested by a Retry-After header, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v %v: %s", e.Response.Request.Method, e.Response.Request.URL, e.Response.Status)
+}
+
+// Temporary reports whether the request may succeed if it is repeated later,
+// which is the case for 429 Too Many Requests and 5xx statuses.
+func (e *StatusError) Temporary() bool {
+	code := e.Response.StatusCode
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func newStatusError(r *http.Response) *StatusError {
+	e := &StatusError{Response: r}
+	if secs, err := strconv.Atoi(r.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return e
+}
+
+// Do carries out a request and stores the result in v. A *StatusError is
+// returned if the response status is not 2xx, in which case the body is not
+// decoded into v and the Response is returned with the error.
 func (api *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
 	req = req.WithContext(ctx)
 
@@ -136,6 +185,9 @@ func (api *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*R
 	defer resp.Body.Close()
 
 	response := makeResponse(resp)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return response, newStatusError(resp)
+	}
 
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
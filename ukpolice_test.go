@@ -40,7 +40,9 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -122,6 +124,68 @@ func TestDo_requestError(t *testing.T) {
 	}
 }
 
+func TestDo_statusError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest("GET", ".", nil)
+	var body []string
+	_, err := client.Do(context.Background(), req, &body)
+	e, ok := err.(*StatusError)
+	if !ok {
+		t.Fatalf("expected *StatusError; got %v", err)
+	}
+	if e.Response.StatusCode != http.StatusServiceUnavailable || !e.Temporary() || e.RetryAfter != 30*time.Second {
+		t.Errorf("StatusError is %+v", e)
+	}
+}
+
+func TestDo_statusErrorNotFound(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `["a"]`)
+	})
+
+	req, _ := client.NewRequest("GET", ".", nil)
+	var body []string
+	resp, err := client.Do(context.Background(), req, &body)
+	e, ok := err.(*StatusError)
+	if !ok {
+		t.Fatalf("expected *StatusError; got %v", err)
+	}
+	if e.Temporary() || !strings.Contains(e.Error(), "404") {
+		t.Errorf("StatusError for 404 is %v, temporary %v", e, e.Temporary())
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Do returned response %v, want the 404 response", resp)
+	}
+	if body != nil {
+		t.Errorf("Do decoded the body of a 404 response into %v", body)
+	}
+}
+
+func TestForceService_GetForceDetails_notFound(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/forces/atlantis", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := client.Force.GetForceDetails(context.Background(), "atlantis")
+	if e, ok := err.(*StatusError); !ok || e.Response.StatusCode != http.StatusNotFound {
+		t.Errorf("Force.GetForceDetails returned error %v, want a 404 StatusError", err)
+	}
+}
+
 func TestDo_jsonSyntaxError(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
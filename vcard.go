@@ -0,0 +1,235 @@
+package ukpolice
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VCard is a contact card that can be written in the vCard 4.0 format
+// described by RFC 6350.
+type VCard struct {
+	// Kind is "individual" for people and "org" for neighbourhoods.
+	Kind string `json:"kind"`
+	// FormattedName is the name as displayed, written as FN.
+	FormattedName string `json:"formatted_name"`
+	// Title is the rank of an officer.
+	Title string `json:"title,omitempty"`
+	// Organisation holds the force followed by any neighbourhood.
+	Organisation []string `json:"organisation,omitempty"`
+	// Note is written as plain text.
+	Note     string    `json:"note,omitempty"`
+	Contacts []Contact `json:"contacts,omitempty"`
+	// UID identifies the card across exports.
+	UID string `json:"uid"`
+}
+
+func (v VCard) String() string {
+	return Stringify(v)
+}
+
+// vcardUID returns a name-based URN derived from parts, so the same person
+// or neighbourhood has the same UID in every export.
+func vcardUID(parts ...string) string {
+	h := sha1.New()
+	for _, p := range parts {
+		io.WriteString(h, squash(p))
+		h.Write([]byte{0})
+	}
+	u := h.Sum(nil)[:16]
+	u[6] = u[6]&0x0f | 0x50 // version 5
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+func personVCard(name, rank, bio string, contacts ContactDetails, organisation ...string) *VCard {
+	return &VCard{
+		Kind:          "individual",
+		FormattedName: strings.Join(strings.Fields(name), " "),
+		Title:         strings.TrimSpace(rank),
+		Organisation:  organisation,
+		Note:          HTMLToText(bio),
+		Contacts:      contacts.Contacts(),
+		UID:           vcardUID(append(append([]string{}, organisation...), name)...),
+	}
+}
+
+// VCard returns a card for the team member with the force and neighbourhood
+// names as the organisation.
+func (n NeighbourhoodTeam) VCard(force, neighbourhood string) *VCard {
+	return personVCard(n.Name, n.Rank, n.Bio, n.ContactDetails, force, neighbourhood)
+}
+
+// VCard returns a card for the officer with the force name as the
+// organisation.
+func (so SeniorOfficer) VCard(force string) *VCard {
+	return personVCard(so.Name, so.Rank, so.Bio, so.ContactDetails, force)
+}
+
+// VCard returns a card for the neighbourhood's own contact details.
+func (n Neighbourhood) VCard(force string) *VCard {
+	name := n.Name
+	if name == "" {
+		name = n.ID
+	}
+	v := &VCard{
+		Kind:          "org",
+		FormattedName: name,
+		Organisation:  []string{force, name},
+		Note:          n.DescriptionText(),
+		Contacts:      n.ContactDetails.Contacts(),
+		UID:           vcardUID(force, n.ID),
+	}
+	if n.ForceURL != "" {
+		v.Contacts = append(v.Contacts, ParseContact("web", n.ForceURL))
+	}
+	return v
+}
+
+// ForceVCards returns cards for the senior officers of a force followed by
+// each neighbourhood of a crawl of it and the members of its team. The name
+// of the force is used as the organisation. crawl may be nil.
+func ForceVCards(force Force, officers []SeniorOfficer, crawl *ForceCrawl) []*VCard {
+	org := force.Name
+	if org == "" {
+		org = force.ID
+	}
+
+	var cards []*VCard
+	for _, so := range officers {
+		cards = append(cards, so.VCard(org))
+	}
+	if crawl == nil {
+		return cards
+	}
+	for _, d := range crawl.Neighbourhoods {
+		n := d.Neighbourhood
+		cards = append(cards, n.VCard(org))
+		name := n.Name
+		if name == "" {
+			name = n.ID
+		}
+		for _, member := range d.Team {
+			cards = append(cards, member.VCard(org, name))
+		}
+	}
+	return cards
+}
+
+// Encode writes the card to w.
+func (v *VCard) Encode(w io.Writer) error {
+	return WriteVCards(w, v)
+}
+
+// WriteVCards writes the cards to w one after another, as expected in a .vcf
+// file.
+func WriteVCards(w io.Writer, cards ...*VCard) error {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	for _, v := range cards {
+		v.encode(cw)
+	}
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+func (v *VCard) encode(cw *contentWriter) {
+	cw.line("BEGIN", "VCARD")
+	cw.line("VERSION", "4.0")
+	cw.line("PRODID", "-//go-ukpolice//Contacts//EN")
+	if v.Kind != "" {
+		cw.line("KIND", v.Kind)
+	}
+	cw.line("FN", icalText(v.FormattedName))
+	if v.Kind == "individual" {
+		cw.line("N", structuredName(v.FormattedName))
+	}
+	if v.Title != "" {
+		cw.line("TITLE", icalText(v.Title))
+	}
+	if len(v.Organisation) > 0 {
+		var parts []string
+		for _, o := range v.Organisation {
+			if o != "" {
+				parts = append(parts, icalText(o))
+			}
+		}
+		cw.line("ORG", strings.Join(parts, ";"))
+	}
+	for _, c := range v.Contacts {
+		if name, value := vcardProperty(c); name != "" {
+			cw.line(name, value)
+		}
+	}
+	if v.Note != "" {
+		cw.line("NOTE", icalText(v.Note))
+	}
+	cw.line("UID", v.UID)
+	cw.line("END", "VCARD")
+}
+
+// vcardTel returns the TEL property of a telephone number with the types
+// given. UK numbers are written as global tel URIs, as RFC 3966 requires of
+// a tel URI without a phone-context, and numbers that cannot be made global,
+// such as 101, are written as text.
+func vcardTel(types string, c Contact) (string, string) {
+	number := strings.TrimPrefix(c.URL, "tel:")
+	if !strings.ContainsAny(number, "0123456789") {
+		return "", ""
+	}
+	switch {
+	case strings.HasPrefix(number, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = "+" + number[2:]
+	case strings.HasPrefix(number, "0") && (len(number) == 10 || len(number) == 11):
+		number = "+44" + number[1:]
+	default:
+		return `TEL;TYPE="` + types + `"`, icalText(c.Value)
+	}
+	return `TEL;VALUE=uri;TYPE="` + types + `"`, "tel:" + number
+}
+
+// structuredName returns the N property of a name, taking the last word as
+// the family name and the words before it as given names.
+func structuredName(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ";;;;"
+	}
+	family := words[len(words)-1]
+	given := strings.Join(words[:len(words)-1], " ")
+	return icalText(family) + ";" + icalText(given) + ";;;"
+}
+
+// vcardProperty returns the name, with parameters, and value of the vCard
+// property for a contact, or an empty name if it has none. Telephone numbers
+// without digits, such as "TBC", have none.
+func vcardProperty(c Contact) (string, string) {
+	switch c.Kind {
+	case ContactEmail:
+		return "EMAIL;TYPE=work", icalText(c.Value)
+	case ContactTelephone:
+		return vcardTel("work,voice", c)
+	case ContactMobile:
+		return vcardTel("work,cell", c)
+	case ContactFax:
+		return vcardTel("work,fax", c)
+	case ContactWeb:
+		return "URL;TYPE=work", c.URL
+	case ContactAddress:
+		return "ADR;TYPE=work", ";;" + icalText(tidyAddress(c.Value)) + ";;;;"
+	case ContactSocial:
+		if c.URL == "" {
+			return "", ""
+		}
+		// SOCIALPROFILE is defined by RFC 9554.
+		return "SOCIALPROFILE;SERVICE-TYPE=" + string(c.Platform), c.URL
+	}
+	if c.URL != "" {
+		return "URL", c.URL
+	}
+	return "", ""
+}
@@ -0,0 +1,88 @@
+package ukpolice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNeighbourhoodTeam_VCard(t *testing.T) {
+	member := NeighbourhoodTeam{
+		Name: "Jane  Smith",
+		Rank: "Sergeant",
+		Bio:  "<p>Jane leads the city centre team; she joined in 2005.</p>",
+		ContactDetails: ContactDetails{
+			"email":     "jane.smith@leicestershire.pnn.police.uk",
+			"telephone": "0116 222 2222",
+			"mobile":    "07700 900123",
+			"fax":       "n/a",
+			"phone":     "101",
+			"twitter":   "https://twitter.com/SgtSmith",
+		},
+	}
+
+	var buf bytes.Buffer
+	card := member.VCard("Leicestershire Police", "City Centre")
+	if err := card.Encode(&buf); err != nil {
+		t.Fatalf("VCard.Encode returned error: '%s'", err)
+	}
+
+	want := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"PRODID:-//go-ukpolice//Contacts//EN\r\n" +
+		"KIND:individual\r\n" +
+		"FN:Jane Smith\r\n" +
+		"N:Smith;Jane;;;\r\n" +
+		"TITLE:Sergeant\r\n" +
+		"ORG:Leicestershire Police;City Centre\r\n" +
+		"EMAIL;TYPE=work:jane.smith@leicestershire.pnn.police.uk\r\n" +
+		"TEL;VALUE=uri;TYPE=\"work,cell\":tel:+447700900123\r\n" +
+		"TEL;TYPE=\"work,voice\":101\r\n" +
+		"TEL;VALUE=uri;TYPE=\"work,voice\":tel:+441162222222\r\n" +
+		"SOCIALPROFILE;SERVICE-TYPE=twitter:https://twitter.com/SgtSmith\r\n" +
+		"NOTE:Jane leads the city centre team\\; she joined in 2005.\r\n" +
+		"UID:" + card.UID + "\r\n" +
+		"END:VCARD\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("VCard.Encode wrote\n%q\nwant\n%q", got, want)
+	}
+
+	if !strings.HasPrefix(card.UID, "urn:uuid:") || len(card.UID) != 45 || card.UID[23] != '5' {
+		t.Errorf("VCard.UID is %q, want a version 5 UUID URN", card.UID)
+	}
+	if again := member.VCard("Leicestershire Police", "City Centre"); again.UID != card.UID {
+		t.Errorf("VCard.UID is not stable: %q, %q", card.UID, again.UID)
+	}
+}
+
+func TestForceVCards(t *testing.T) {
+	force := Force{ID: "leicestershire", Name: "Leicestershire Police"}
+	officers := []SeniorOfficer{{Name: "Simon Cole", Rank: "Chief Constable"}}
+	crawl := &ForceCrawl{Force: "leicestershire", Neighbourhoods: []NeighbourhoodDetails{{
+		Neighbourhood: Neighbourhood{ID: "NC04", Name: "City Centre",
+			ContactDetails: ContactDetails{"email": "centre@leicestershire.pnn.police.uk"}},
+		Team: []NeighbourhoodTeam{{Name: "Tom Jones", Rank: "PCSO"}},
+	}}}
+
+	cards := ForceVCards(force, officers, crawl)
+	if len(cards) != 3 {
+		t.Fatalf("ForceVCards returned %d cards, want 3", len(cards))
+	}
+	if cards[1].Kind != "org" || cards[1].FormattedName != "City Centre" {
+		t.Errorf("ForceVCards()[1] is %v, want the neighbourhood", cards[1])
+	}
+	if got := cards[2].Organisation; len(got) != 2 || got[1] != "City Centre" {
+		t.Errorf("ForceVCards()[2].Organisation is %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteVCards(&buf, cards...); err != nil {
+		t.Fatalf("WriteVCards returned error: '%s'", err)
+	}
+	if n := strings.Count(buf.String(), "BEGIN:VCARD\r\n"); n != 3 {
+		t.Errorf("WriteVCards wrote %d cards, want 3", n)
+	}
+	if !strings.Contains(buf.String(), "KIND:org\r\nFN:City Centre\r\nORG:Leicestershire Police;City Centre\r\n") {
+		t.Errorf("WriteVCards wrote\n%s", buf.String())
+	}
+}
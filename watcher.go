@@ -0,0 +1,314 @@
+package ukpolice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReleaseEventType identifies the kind of change a Watcher observed.
+type ReleaseEventType string
+
+// Release event types.
+const (
+	// LastUpdatedChanged is emitted whenever the date returned by
+	// GetLastUpdated changes.
+	LastUpdatedChanged ReleaseEventType = "last-updated-changed"
+	// CrimeMonthPublished is emitted when a new month of street level crime
+	// data becomes available.
+	CrimeMonthPublished ReleaseEventType = "crime-month-published"
+	// CrimeMonthWithdrawn is emitted when a month is no longer available,
+	// which happens as the oldest data is retired.
+	CrimeMonthWithdrawn ReleaseEventType = "crime-month-withdrawn"
+	// StopAndSearchForceAdded is emitted when a force is added to the stop
+	// and search availability of a month that was already published.
+	StopAndSearchForceAdded ReleaseEventType = "stop-and-search-force-added"
+	// StopAndSearchForceRemoved is emitted when a force is removed from the
+	// stop and search availability of a month.
+	StopAndSearchForceRemoved ReleaseEventType = "stop-and-search-force-removed"
+)
+
+// ReleaseEvent describes a change in the data published by the API.
+type ReleaseEvent struct {
+	Type        ReleaseEventType `json:"type"`
+	LastUpdated string           `json:"last_updated"`
+	Month       string           `json:"month,omitempty"`
+	Force       string           `json:"force,omitempty"`
+	// Forces holds the forces with stop and search data for a newly
+	// published month.
+	Forces []string `json:"forces,omitempty"`
+}
+
+func (e ReleaseEvent) String() string {
+	return Stringify(e)
+}
+
+// WatchState is the state a Watcher compares each poll against.
+type WatchState struct {
+	LastUpdated string `json:"last_updated"`
+	// StopAndSearch holds the forces with stop and search data for each
+	// available month.
+	StopAndSearch map[string][]string `json:"stop_and_search"`
+}
+
+// Watcher polls the API for new data releases and emits an event for each
+// change it observes. The first poll without a saved state records the
+// current state without emitting events.
+type Watcher struct {
+	// Interval is the time between polls made by Run. An hour is used if it
+	// is not positive.
+	Interval time.Duration
+	// StatePath, if set, is the file the last seen state is loaded from and
+	// saved to, so that changes made while the watcher was stopped are
+	// reported when it restarts.
+	StatePath string
+	// OnEvent, if set, is called with each event.
+	OnEvent func(ReleaseEvent)
+	// OnError, if set, is called with errors encountered by Run. Run keeps
+	// polling after an error.
+	OnError func(error)
+
+	api     *Client
+	mu      sync.Mutex
+	state   *WatchState
+	events  chan ReleaseEvent
+	running bool
+}
+
+// NewWatcher returns a Watcher polling hourly. If statePath is not empty and
+// the file exists, the state saved there is loaded.
+func NewWatcher(client *Client, statePath string) (*Watcher, error) {
+	w := &Watcher{Interval: time.Hour, StatePath: statePath, api: client}
+	if statePath == "" {
+		return w, nil
+	}
+
+	b, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state WatchState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, err
+	}
+	w.state = &state
+	return w, nil
+}
+
+// Events returns a channel on which events are delivered. Once Events has
+// been called, before or while Run is running, Run blocks until each event is
+// received or its context is done. The channel is closed when Run returns,
+// and a later call to Events returns a new channel.
+func (w *Watcher) Events() <-chan ReleaseEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.events == nil {
+		w.events = make(chan ReleaseEvent)
+	}
+	return w.events
+}
+
+// State returns a copy of the last seen state, or nil if nothing has been
+// seen yet.
+func (w *Watcher) State() *WatchState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.state == nil {
+		return nil
+	}
+	state := &WatchState{
+		LastUpdated:   w.state.LastUpdated,
+		StopAndSearch: make(map[string][]string, len(w.state.StopAndSearch)),
+	}
+	for month, forces := range w.state.StopAndSearch {
+		state.StopAndSearch[month] = append([]string(nil), forces...)
+	}
+	return state
+}
+
+// Poll checks the API once and returns the changes since the last poll. The
+// state is saved if StatePath is set. If it cannot be saved an error is
+// returned and the last seen state is kept, so the changes are reported again
+// by the next poll. OnEvent is called for each change but events are not sent
+// to the Events channel.
+func (w *Watcher) Poll(ctx context.Context) ([]ReleaseEvent, error) {
+	updated, _, err := w.api.Crime.GetLastUpdated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var date string
+	if updated != nil {
+		date = updated.Date
+	}
+
+	w.mu.Lock()
+	previous := w.state
+	w.mu.Unlock()
+	if previous != nil && previous.LastUpdated == date {
+		return nil, nil
+	}
+
+	info, _, err := w.api.Availability.GetAvailabilityInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := &WatchState{LastUpdated: date, StopAndSearch: make(map[string][]string)}
+	for _, a := range info {
+		forces := append([]string(nil), a.StopAndSearch...)
+		sort.Strings(forces)
+		current.StopAndSearch[a.Date] = forces
+	}
+
+	var events []ReleaseEvent
+	if previous != nil {
+		events = diffWatchState(previous, current)
+	}
+
+	if err := w.save(current); err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	w.state = current
+	w.mu.Unlock()
+
+	if w.OnEvent != nil {
+		for _, e := range events {
+			w.OnEvent(e)
+		}
+	}
+	return events, nil
+}
+
+// save writes state to StatePath.
+func (w *Watcher) save(state *WatchState) error {
+	if w.StatePath == "" {
+		return nil
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFile(w.StatePath, b)
+}
+
+// Run polls immediately and then every Interval until ctx is done, returning
+// ctx.Err(). An error is returned if the watcher is already running.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return errors.New("ukpolice: watcher is already running")
+	}
+	w.running = true
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		if w.events != nil {
+			close(w.events)
+			w.events = nil
+		}
+		w.running = false
+		w.mu.Unlock()
+	}()
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		changes, err := w.Poll(ctx)
+		if err != nil && ctx.Err() == nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		w.mu.Lock()
+		events := w.events
+		w.mu.Unlock()
+		for _, e := range changes {
+			if events == nil {
+				break
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// diffWatchState returns the events describing the change from previous to
+// current in a stable order.
+func diffWatchState(previous, current *WatchState) []ReleaseEvent {
+	events := []ReleaseEvent{{Type: LastUpdatedChanged, LastUpdated: current.LastUpdated}}
+	event := func(t ReleaseEventType, month, force string, forces []string) {
+		events = append(events, ReleaseEvent{
+			Type:        t,
+			LastUpdated: current.LastUpdated,
+			Month:       month,
+			Force:       force,
+			Forces:      forces,
+		})
+	}
+
+	for _, month := range sortedMonths(current.StopAndSearch) {
+		forces := current.StopAndSearch[month]
+		before, ok := previous.StopAndSearch[month]
+		if !ok {
+			event(CrimeMonthPublished, month, "", forces)
+			continue
+		}
+		for _, f := range difference(forces, before) {
+			event(StopAndSearchForceAdded, month, f, nil)
+		}
+		for _, f := range difference(before, forces) {
+			event(StopAndSearchForceRemoved, month, f, nil)
+		}
+	}
+
+	for _, month := range sortedMonths(previous.StopAndSearch) {
+		if _, ok := current.StopAndSearch[month]; !ok {
+			event(CrimeMonthWithdrawn, month, "", nil)
+		}
+	}
+	return events
+}
+
+func sortedMonths(m map[string][]string) []string {
+	months := make([]string, 0, len(m))
+	for month := range m {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months
+}
+
+// difference returns the elements of a that are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
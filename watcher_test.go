@@ -0,0 +1,235 @@
+package ukpolice
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// releaseServer serves the last updated date and availability information
+// held in the returned pointers.
+func releaseServer(mux *http.ServeMux) (*string, *string) {
+	updated := "2017-02-01"
+	availability := `[
+		{"date": "2017-01", "stop-and-search": ["cleveland", "durham"]},
+		{"date": "2016-12", "stop-and-search": ["cleveland"]}
+	]`
+	mux.HandleFunc("/crime-last-updated", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"date": %q}`, updated)
+	})
+	mux.HandleFunc("/crimes-street-dates", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, availability)
+	})
+	return &updated, &availability
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	updated, availability := releaseServer(mux)
+
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	w, err := NewWatcher(client, path)
+	if err != nil {
+		t.Fatalf("NewWatcher returned error: '%s'", err)
+	}
+	events, err := w.Poll(context.Background())
+	if err != nil || len(events) != 0 {
+		t.Fatalf("first Watcher.Poll returned %v, %v; want no events", events, err)
+	}
+
+	// nothing has changed.
+	if events, _ := w.Poll(context.Background()); len(events) != 0 {
+		t.Errorf("Watcher.Poll returned %v without a release", events)
+	}
+
+	*updated = "2017-03-01"
+	*availability = `[
+		{"date": "2017-02", "stop-and-search": ["durham"]},
+		{"date": "2017-01", "stop-and-search": ["cleveland", "dyfed-powys"]}
+	]`
+
+	// a new watcher picks up the saved state.
+	w, err = NewWatcher(client, path)
+	if err != nil {
+		t.Fatalf("NewWatcher returned error: '%s'", err)
+	}
+	var received []ReleaseEvent
+	w.OnEvent = func(e ReleaseEvent) { received = append(received, e) }
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Watcher.Poll returned error: '%s'", err)
+	}
+
+	want := []ReleaseEvent{
+		{Type: LastUpdatedChanged, LastUpdated: "2017-03-01"},
+		{Type: StopAndSearchForceAdded, LastUpdated: "2017-03-01", Month: "2017-01", Force: "dyfed-powys"},
+		{Type: StopAndSearchForceRemoved, LastUpdated: "2017-03-01", Month: "2017-01", Force: "durham"},
+		{Type: CrimeMonthPublished, LastUpdated: "2017-03-01", Month: "2017-02", Forces: []string{"durham"}},
+		{Type: CrimeMonthWithdrawn, LastUpdated: "2017-03-01", Month: "2016-12"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Watcher.Poll returned %v, want %v", events, want)
+	}
+	if !reflect.DeepEqual(received, want) {
+		t.Errorf("Watcher.OnEvent received %v, want %v", received, want)
+	}
+	if state := w.State(); state.LastUpdated != "2017-03-01" || len(state.StopAndSearch) != 2 {
+		t.Errorf("Watcher.State returned %v", state)
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	updated, _ := releaseServer(mux)
+
+	w, _ := NewWatcher(client, "")
+	w.Interval = 10 * time.Millisecond
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: '%s'", err)
+	}
+	*updated = "2017-03-01"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	events := w.Events()
+	go func() { done <- w.Run(ctx) }()
+
+	select {
+	case e := <-events:
+		if e.Type != LastUpdatedChanged || e.LastUpdated != "2017-03-01" {
+			t.Errorf("Watcher.Events delivered %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watcher.Events delivered nothing")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watcher.Run returned %v, want %v", err, context.Canceled)
+	}
+	if _, ok := <-events; ok {
+		t.Errorf("Watcher.Events channel was not closed")
+	}
+}
+
+// waitRunning waits until w is running.
+func waitRunning(w *Watcher) {
+	for {
+		w.mu.Lock()
+		running := w.running
+		w.mu.Unlock()
+		if running {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatcher_RunTwice(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	releaseServer(mux)
+
+	// an Interval of zero falls back to an hour.
+	w, _ := NewWatcher(client, "")
+	w.Interval = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	waitRunning(w)
+
+	if err := w.Run(ctx); err == nil {
+		t.Errorf("second Watcher.Run should have returned an error")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watcher.Run returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWatcher_EventsAfterRun(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var updated atomic.Value
+	updated.Store("2017-02-01")
+	mux.HandleFunc("/crime-last-updated", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"date": %q}`, updated.Load())
+	})
+	mux.HandleFunc("/crimes-street-dates", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"date": "2017-01", "stop-and-search": ["cleveland"]}]`)
+	})
+
+	w, _ := NewWatcher(client, "")
+	w.Interval = 10 * time.Millisecond
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: '%s'", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	waitRunning(w)
+
+	events := w.Events()
+	updated.Store("2017-03-01")
+	select {
+	case e := <-events:
+		if e.Type != LastUpdatedChanged {
+			t.Errorf("Watcher.Events delivered %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watcher.Events obtained during Run delivered nothing")
+	}
+
+	cancel()
+	<-done
+	if _, ok := <-events; ok {
+		t.Errorf("Watcher.Events channel was not closed")
+	}
+}
+
+func TestWatcher_PollSaveError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	updated, _ := releaseServer(mux)
+
+	dir, err := ioutil.TempDir("", "ukpolice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w, _ := NewWatcher(client, filepath.Join(dir, "state.json"))
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Watcher.Poll returned error: '%s'", err)
+	}
+	*updated = "2017-03-01"
+
+	w.StatePath = filepath.Join(dir, "missing", "state.json")
+	if events, err := w.Poll(context.Background()); err == nil || len(events) != 0 {
+		t.Errorf("Watcher.Poll returned %v, %v; want a save error", events, err)
+	}
+	if state := w.State(); state.LastUpdated != "2017-02-01" {
+		t.Errorf("Watcher.State is %v after a failed save, want the previous state", state)
+	}
+
+	w.StatePath = filepath.Join(dir, "state.json")
+	if events, err := w.Poll(context.Background()); err != nil || len(events) == 0 {
+		t.Errorf("Watcher.Poll returned %v, %v; want the changes reported again", events, err)
+	}
+}